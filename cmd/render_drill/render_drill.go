@@ -0,0 +1,87 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/Bradbev/hockeygame/src/hg"
+)
+
+func main() {
+	drill := flag.String("drill", hg.DefaultDrillPath, "drill file to render")
+	out := flag.String("out", "test_output", "directory to write the PNG sequence to")
+	prefix := flag.String("prefix", "frame", "file name prefix for each PNG")
+	steps := flag.Int("steps", 0, "interpolation steps per frame, 0 for one still per frame")
+	svgPath := flag.String("svg", "", "write an SVG diagram of the drill here instead of PNGs")
+	svgFrame := flag.Int("frame", 0, "with -svg, the only frame layer shown (from 1), 0 to show all")
+	rinkSVG := flag.String("rink-svg", "", "with -svg, a drawing to use in place of the rink's markings")
+	gifPath := flag.String("gif", "", "write an animated GIF of the drill here instead of PNGs")
+	fps := flag.Float64("fps", hg.DefaultGIFOptions.FPS, "GIF samples per second of drill time")
+	hold := flag.Float64("hold", hg.DefaultGIFOptions.HoldSeconds, "seconds the GIF pauses on the final positions")
+	flag.Parse()
+
+	d, err := hg.LoadDrillFile(*drill)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *svgPath != "" {
+		if err := writeSVG(d, *svgPath, *rinkSVG, *svgFrame-1); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	err = hg.RunOffscreen(func() error {
+		renderer := hg.NewDrillRenderer(d)
+		if *gifPath != "" {
+			return writeGIF(renderer, *gifPath, hg.GIFOptions{FPS: *fps, HoldSeconds: *hold})
+		}
+		files, err := renderer.WritePNGs(*out, *prefix, *steps)
+		for _, f := range files {
+			fmt.Println(f)
+		}
+		return err
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func writeGIF(renderer *hg.DrillRenderer, path string, opts hg.GIFOptions) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = renderer.WriteGIF(f, opts)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		fmt.Println(path)
+	}
+	return err
+}
+
+// writeSVG needs no graphics context, so it runs outside RunOffscreen.
+func writeSVG(d *hg.DrillFile, path, rinkPath string, visibleFrame int) error {
+	var rink []byte
+	if rinkPath != "" {
+		var err error
+		if rink, err = os.ReadFile(rinkPath); err != nil {
+			return err
+		}
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	err = hg.WriteDrillSVG(f, d, hg.SVGOptions{RinkSVG: rink, VisibleFrame: visibleFrame})
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		fmt.Println(path)
+	}
+	return err
+}
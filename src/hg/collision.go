@@ -0,0 +1,193 @@
+package hg
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// collisionSamples is how many steps through a frame collisions are checked at.
+const collisionSamples = 100
+
+// Collision is a stretch of a frame where two players overlap.  Start, End
+// and Closest are fractions of the frame.
+type Collision struct {
+	A, B       int
+	Start, End float32
+	// Closest is when the players are nearest, Distance apart centre to centre.
+	Closest  float32
+	Distance float32
+}
+
+// sampleCentres returns every player's centre at each collision sample.
+func sampleCentres(players []*Player) [][]SkatePoint {
+	centres := make([][]SkatePoint, len(players))
+	for i, p := range players {
+		centres[i] = make([]SkatePoint, collisionSamples+1)
+		for s := range centres[i] {
+			centres[i][s] = p.CentreAt(float32(s) / collisionSamples)
+		}
+	}
+	return centres
+}
+
+// FindCollisions returns the times during the frame when the circles of two
+// players in the group overlap as they skate their paths.
+func FindCollisions(players *PlayerGroup) []Collision {
+	var found []Collision
+	ps := players.Players
+	centres := sampleCentres(ps)
+	for i := range ps {
+		for j := i + 1; j < len(ps); j++ {
+			found = append(found, pairCollisions(ps[i].Id, ps[j].Id, centres[i], centres[j])...)
+		}
+	}
+	return found
+}
+
+func pairCollisions(a, b int, ca, cb []SkatePoint) []Collision {
+	var found []Collision
+	var c *Collision
+	for s := range ca {
+		t := float32(s) / collisionSamples
+		d := ca[s].Sub(cb[s]).Length()
+		if d >= 2*playerRadius {
+			c = nil
+			continue
+		}
+		if c == nil {
+			found = append(found, Collision{A: a, B: b, Start: t, Distance: d, Closest: t})
+			c = &found[len(found)-1]
+		}
+		c.End = t
+		if d < c.Distance {
+			c.Distance, c.Closest = d, t
+		}
+	}
+	return found
+}
+
+// At reports whether the collision is happening at fraction.
+func (c *Collision) At(fraction float32) bool {
+	return fraction >= c.Start && fraction <= c.End
+}
+
+// collisionNudgeWindow is how much of the path either side of the closest
+// approach is bent by ProposeNudge, as a fraction of the path's length.
+const collisionNudgeWindow = 0.15
+
+// ProposeNudge suggests a new skate path for one of the players in c that
+// bends it away from the other around their closest approach.  It returns
+// the player to change and their new path, or nil if neither is skating.
+// The proposal may not clear every collision the player has, so callers
+// should check again.
+func ProposeNudge(players *PlayerGroup, c Collision) (*Player, Path) {
+	mover, other := players.ById(c.B), players.ById(c.A)
+	if mover == nil || other == nil {
+		return nil, nil
+	}
+	if mover.Path == nil || len(mover.Path.Polyline()) < 2 {
+		mover, other = other, mover
+	}
+	if mover.Path == nil || len(mover.Path.Polyline()) < 2 {
+		return nil, nil
+	}
+
+	nudged := &Player{Id: mover.Id, Path: mover.Path, Timing: mover.Timing, Skating: mover.Skating}
+	for range 8 {
+		at := c.Closest
+		away := nudged.CentreAt(at).Sub(other.CentreAt(at))
+		dist := away.Length()
+		if dist >= 2*playerRadius {
+			break
+		}
+		// Push sideways to the direction of travel, towards the side the
+		// other player is not on.  Pushing along the path just changes timing.
+		along := nudged.CentreAt(min(1, at+0.01)).Sub(nudged.CentreAt(max(0, at-0.01)))
+		side := SkatePoint{X: -along.Y, Y: along.X}.Normalize()
+		if side.X*away.X+side.Y*away.Y < 0 {
+			side = side.Mul(-1)
+		}
+		nudged.Path = nudgePath(nudged.Path, nudged.pathFraction(at), side.Mul(2*playerRadius-dist+4))
+		var closest float32 = math.MaxFloat32
+		for s := 0; s <= collisionSamples; s++ {
+			t := float32(s) / collisionSamples
+			if d := nudged.CentreAt(t).Sub(other.CentreAt(t)).Length(); d < closest {
+				closest, c.Closest = d, t
+			}
+		}
+	}
+	carryFacing(mover.Path, nudged.Path)
+	return mover, nudged.Path
+}
+
+// nudgeSteps is how many evenly spaced points a nudged path is rebuilt from,
+// so that even a straight two point path has points to bend.
+const nudgeSteps = 50
+
+// nudgePath returns path moved near fraction by offset, easing off to nothing
+// collisionNudgeWindow either side, simplified back to an editable path the
+// way a drawn stroke is.
+func nudgePath(path Path, fraction float32, offset SkatePoint) *SkatePathWithRadius {
+	moved := make([]SkatePoint, nudgeSteps+1)
+	for i := range moved {
+		t := float32(i) / nudgeSteps
+		moved[i] = path.Interpolate(t)
+		if d := math.Abs(float64(t - fraction)); d < collisionNudgeWindow {
+			w := float32(0.5 * (1 + math.Cos(math.Pi*d/collisionNudgeWindow)))
+			moved[i] = moved[i].Add(offset.Mul(w))
+		}
+	}
+	return SimplifyStroke(moved, SimplifyTolerance)
+}
+
+// collisionView finds and shows collisions in the editor.
+type collisionView struct {
+	Show  bool
+	found []Collision
+}
+
+var collisionColor = color.RGBA{0xe0, 0x10, 0x10, 0xff}
+
+// Update finds the collisions in players.
+func (v *collisionView) Update(players *PlayerGroup) {
+	v.found = nil
+	if v.Show {
+		v.found = FindCollisions(players)
+	}
+}
+
+// Draw rings both players where each collision is worst, solidly if it is
+// happening at fraction, and marks when they happen on the timeline.
+func (v *collisionView) Draw(screen *ebiten.Image, players *PlayerGroup, fraction float32, timeline *Timeline, frames []frame, frameIndex int) {
+	start := frameStartTime(frames, frameIndex)
+	duration := frames[frameIndex].DurationSeconds
+	for _, c := range v.found {
+		width := float32(1.5)
+		if c.At(fraction) {
+			width = 4
+		}
+		for _, id := range []int{c.A, c.B} {
+			if p := players.ById(id); p != nil {
+				centre := p.CentreAt(c.Closest)
+				vector.StrokeCircle(screen, centre.X, centre.Y, playerRadius+3, width, collisionColor, true)
+			}
+		}
+		timeline.DrawSpan(screen, frames, start+float64(c.Start)*duration, start+float64(c.End)*duration, collisionColor)
+	}
+}
+
+// Count is the number of collisions found.
+func (v *collisionView) Count() int {
+	return len(v.found)
+}
+
+// First returns the first collision found.
+func (v *collisionView) First() (Collision, bool) {
+	if len(v.found) == 0 {
+		return Collision{}, false
+	}
+	return v.found[0], true
+}
@@ -0,0 +1,44 @@
+package hg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindCollisions(t *testing.T) {
+	players := &PlayerGroup{}
+	// 1 skates straight through 2, who stands still half way along.
+	players.Add(&Player{Id: 1, Path: &SkatePath{Points: []SkatePoint{{X: 20, Y: 100}, {X: 420, Y: 100}}}})
+	players.Add(&Player{Id: 2, X: 200, Y: 80})
+	players.Add(&Player{Id: 3, X: 200, Y: 400})
+
+	found := FindCollisions(players)
+	require.Len(t, found, 1)
+	c := found[0]
+	assert.Equal(t, 1, c.A)
+	assert.Equal(t, 2, c.B)
+	// 1 starts from rest, so reaches 2 a little after half way through the frame.
+	assert.InDelta(t, 0.58, c.Closest, 0.001)
+	assert.Less(t, c.Distance, float32(5))
+	assert.InDelta(t, 0.52, c.Start, 0.001)
+	assert.InDelta(t, 0.63, c.End, 0.001)
+	assert.True(t, c.At(0.58))
+	assert.False(t, c.At(0.9))
+
+	player, path := ProposeNudge(players, c)
+	require.NotNil(t, player)
+	assert.Equal(t, 1, player.Id)
+	// The nudge stays an editable path rather than a freehand one.
+	assert.IsType(t, &SkatePathWithRadius{}, path)
+	player.Path = path
+	assert.Empty(t, FindCollisions(players))
+
+	// Nobody is skating, so there is nothing to nudge.
+	players.Add(&Player{Id: 4, X: 210, Y: 400})
+	found = FindCollisions(players)
+	require.Len(t, found, 1)
+	player, _ = ProposeNudge(players, found[0])
+	assert.Nil(t, player)
+}
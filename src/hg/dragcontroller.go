@@ -14,6 +14,7 @@ type MouseController struct {
 	mouseUp          time.Time
 	mouseIsDown      bool
 	doubleClick      bool
+	clicked          bool
 	mx, my           int
 	offsetX, offsetY int
 }
@@ -22,12 +23,19 @@ func (dc *MouseController) IsDoubleClick() bool {
 	return dc.doubleClick
 }
 
+// Clicked reports whether the button was just released without dragging.
+func (dc *MouseController) Clicked() bool {
+	return dc.clicked
+}
+
 func (dc *MouseController) Update() {
 	downTime := inpututil.MouseButtonPressDuration(ebiten.MouseButtonLeft)
 	dc.doubleClick = false
+	dc.clicked = false
 	switch downTime {
 	case 0:
 		if dc.mouseIsDown {
+			dc.clicked = dc.activeCount == 0
 			if time.Since(dc.mouseUp) < 300*time.Millisecond {
 				dc.doubleClick = true
 			}
@@ -0,0 +1,338 @@
+package hg
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// DrillFormatVersion is the version written by DrillFile.Marshal.
+// Bump it whenever the layout changes in a way older files need migrating,
+// and add the upgrade step to drillMigrations.
+const DrillFormatVersion = 4
+
+// DefaultDrillPath is where the editor saves and loads when no other file is chosen.
+const DefaultDrillPath = "saved.json"
+
+// DrillFile is the on-disk drill format, saved as JSON.  Positions are in
+// screen pixels on the rink as Rink fits it to the screen.  Older versions
+// are migrated on load, see drillMigrations, and unknown fields are an error
+// rather than being silently dropped.
+type DrillFile struct {
+	// Version is DrillFormatVersion when saved.  Version 1 is the original
+	// saved.json dump of NextPlayerId and Frames, version 2 only had
+	// freehand paths and version 3 kept a player's SkatePath, RadiusPath and
+	// SplinePath side by side.
+	Version int
+	// Meta is optional and describes the drill in a DrillLibrary.
+	Meta DrillMeta
+	// Roster is optional, DefaultRoster's two teams without one.  A player's
+	// Team indexes its Teams.
+	Roster Roster
+	// Rink is optional, the whole of an NHL rink without one.
+	Rink         RinkLayout
+	NextPlayerId int
+	// Frames play in order, each with its players, puck and props.
+	Frames []frame
+}
+
+// saveLoadData is the version 1 layout.
+type saveLoadData struct {
+	NextPlayerId int
+	Frames       json.RawMessage
+}
+
+// drillMigrations upgrade raw JSON from the keyed version to the next one.
+var drillMigrations = map[int]func([]byte) ([]byte, error){
+	1: migrateDrillV1,
+	2: migrateDrillV2,
+	3: migrateDrillV3,
+}
+
+func migrateDrillV1(data []byte) ([]byte, error) {
+	sld := saveLoadData{}
+	if err := decodeStrict(data, &sld); err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		Version      int
+		NextPlayerId int
+		Frames       json.RawMessage
+	}{2, sld.NextPlayerId, sld.Frames})
+}
+
+// eachRawPlayer calls f with every player in a drill decoded as generic JSON.
+func eachRawPlayer(raw map[string]any, f func(player map[string]any)) {
+	frames, _ := raw["Frames"].([]any)
+	for _, fr := range frames {
+		frame, _ := fr.(map[string]any)
+		group, _ := frame["Players"].(map[string]any)
+		players, _ := group["Players"].([]any)
+		for _, p := range players {
+			if player, ok := p.(map[string]any); ok {
+				f(player)
+			}
+		}
+	}
+}
+
+// migrateDrillV2 simplifies freehand skate paths into radius paths.  It works
+// on the raw JSON, writing out the version 3 fields by hand, so that it keeps
+// working as the types change.
+func migrateDrillV2(data []byte) ([]byte, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	eachRawPlayer(raw, func(player map[string]any) {
+		path, _ := player["SkatePath"].(map[string]any)
+		if path == nil {
+			return
+		}
+		var points []SkatePoint
+		rawPoints, _ := path["Points"].([]any)
+		for _, pt := range rawPoints {
+			xy, _ := pt.(map[string]any)
+			x, _ := xy["X"].(float64)
+			y, _ := xy["Y"].(float64)
+			points = append(points, SkatePoint{X: float32(x), Y: float32(y)})
+		}
+		if len(points) < 2 {
+			return
+		}
+		rp := SimplifyStroke(points, SimplifyTolerance)
+		rawPoints = nil
+		for _, pt := range rp.Points {
+			rawPoints = append(rawPoints, map[string]any{"X": pt.X, "Y": pt.Y})
+		}
+		player["RadiusPath"] = map[string]any{"Points": rawPoints, "PointRadiuses": rp.PointRadiuses}
+		path["Points"] = nil
+	})
+	raw["Version"] = 3
+	return json.Marshal(raw)
+}
+
+// migrateDrillV3 replaces each player's SkatePath, RadiusPath and SplinePath
+// with a single Path tagged with its Type, and moves the SkatePath timing to
+// Timing.  TargetId is dropped as the path now hangs off its player.
+func migrateDrillV3(data []byte) ([]byte, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	eachRawPlayer(raw, func(player map[string]any) {
+		skate, _ := player["SkatePath"].(map[string]any)
+		var path map[string]any
+		if spline, ok := player["SplinePath"].(map[string]any); ok {
+			path = spline
+			path["Type"] = "spline"
+		} else if radius, ok := player["RadiusPath"].(map[string]any); ok {
+			path = radius
+			path["Type"] = "radius"
+		} else if points, _ := skate["Points"].([]any); len(points) > 0 {
+			path = map[string]any{"Type": "freehand", "Points": points}
+		}
+		if path != nil {
+			delete(path, "TargetId")
+		}
+		timing := map[string]any{"Delay": 0, "Finish": 0, "Ease": ""}
+		for _, key := range []string{"Delay", "Finish", "Ease"} {
+			if v, ok := skate[key]; ok {
+				timing[key] = v
+			}
+		}
+		player["Path"] = path
+		player["Timing"] = timing
+		delete(player, "SkatePath")
+		delete(player, "RadiusPath")
+		delete(player, "SplinePath")
+	})
+	raw["Version"] = 4
+	return json.Marshal(raw)
+}
+
+func decodeStrict(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+// ParseDrill decodes, migrates and validates a drill.
+func ParseDrill(data []byte) (*DrillFile, error) {
+	probe := struct{ Version *int }{}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return nil, fmt.Errorf("drill: %w", err)
+	}
+	version := 1
+	if probe.Version != nil {
+		version = *probe.Version
+	}
+	if version < 1 || version > DrillFormatVersion {
+		return nil, fmt.Errorf("drill: unsupported format version %d (this build reads 1 to %d)", version, DrillFormatVersion)
+	}
+	for ; version < DrillFormatVersion; version++ {
+		var err error
+		data, err = drillMigrations[version](data)
+		if err != nil {
+			return nil, fmt.Errorf("drill: migrating from version %d: %w", version, err)
+		}
+	}
+
+	d := &DrillFile{}
+	if err := decodeStrict(data, d); err != nil {
+		return nil, fmt.Errorf("drill: %w", err)
+	}
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Validate reports every problem found in the drill, or nil if there are none.
+// Frames are numbered from 1, matching the editor.
+func (d *DrillFile) Validate() error {
+	var errs []error
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if len(d.Frames) == 0 {
+		fail("drill has no frames")
+	}
+	if len(d.Roster.Teams) > 0 {
+		if err := d.Roster.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := d.Rink.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	teams := len(d.roster().Teams)
+	maxId := -1
+	for fi, frame := range d.Frames {
+		n := fi + 1
+		if frame.DurationSeconds < 0 {
+			fail("frame %d: negative DurationSeconds %g", n, frame.DurationSeconds)
+		}
+		if frame.Players == nil {
+			fail("frame %d: missing Players", n)
+			continue
+		}
+
+		byId := map[int]*Player{}
+		for i, player := range frame.Players.Players {
+			if player == nil {
+				fail("frame %d: player entry %d is null", n, i)
+				continue
+			}
+			if other, ok := byId[player.Id]; ok {
+				fail("frame %d: duplicate player Id %d (%q and %q)", n, player.Id, other.Symbol, player.Symbol)
+				continue
+			}
+			byId[player.Id] = player
+			maxId = max(maxId, player.Id)
+		}
+		for _, player := range frame.Players.Players {
+			if player == nil {
+				continue
+			}
+			if !slices.Contains(Roles, player.Role) {
+				fail("frame %d: player %d (%q) has unknown Role %q", n, player.Id, player.Symbol, player.Role)
+			}
+			if t := player.Tracking; t != nil {
+				switch {
+				case player.Role != RoleGoalie:
+					fail("frame %d: player %d (%q) has Tracking but is not a goalie", n, player.Id, player.Symbol)
+				case player.Path != nil:
+					fail("frame %d: player %d (%q) has both a Path and Tracking", n, player.Id, player.Symbol)
+				case t.Net != 0 && t.Net != 1:
+					fail("frame %d: player %d (%q) has Tracking Net %d, want 0 or 1", n, player.Id, player.Symbol, t.Net)
+				}
+			}
+			if player.Team < 0 || player.Team >= teams {
+				fail("frame %d: player %d (%q) has Team %d but the Roster has %d teams", n, player.Id, player.Symbol, player.Team, teams)
+			}
+			if l := player.Skating; l != nil && (l.MaxSpeed <= 0 || l.Accel <= 0 || l.Brake <= 0 || l.MaxTurnAccel < 0) {
+				fail("frame %d: player %d (%q) has Skating limits that are not positive", n, player.Id, player.Symbol)
+			}
+			if player.Path != nil {
+				if err := player.Path.Validate(); err != nil {
+					fail("frame %d: player %d (%q) %v", n, player.Id, player.Symbol, err)
+				}
+			}
+			if !slices.Contains(PathNotations, player.Style.Line) {
+				fail("frame %d: player %d (%q) has unknown Style Line %q", n, player.Id, player.Symbol, player.Style.Line)
+			}
+			if !slices.Contains(PathEnds, player.Style.End) {
+				fail("frame %d: player %d (%q) has unknown Style End %q", n, player.Id, player.Symbol, player.Style.End)
+			}
+			if player.Shoots != "" && player.Shoots != ShootsLeft && player.Shoots != ShootsRight {
+				fail("frame %d: player %d (%q) has Shoots %q, want %q or %q", n, player.Id, player.Symbol, player.Shoots, ShootsLeft, ShootsRight)
+			}
+			if t := player.Timing; t.Delay < 0 || t.Finish < 0 || t.Finish > 1 || t.Delay >= t.finish() {
+				fail("frame %d: player %d (%q) has Timing Delay %g and Finish %g, Delay must come before Finish within 0 to 1",
+					n, player.Id, player.Symbol, t.Delay, t.Finish)
+			}
+			if !slices.Contains(EaseCurves, player.Timing.Ease) {
+				fail("frame %d: player %d (%q) has unknown Timing Ease %q", n, player.Id, player.Symbol, player.Timing.Ease)
+			}
+		}
+		if frame.Puck != nil {
+			errs = append(errs, frame.Puck.validate(n, byId)...)
+		}
+		for i, prop := range frame.Props {
+			if prop == nil {
+				fail("frame %d: prop entry %d is null", n, i)
+			} else if !slices.Contains(PropKinds, prop.Kind) {
+				fail("frame %d: prop %d has unknown Kind %q", n, i+1, prop.Kind)
+			}
+		}
+	}
+	if d.NextPlayerId <= maxId {
+		fail("NextPlayerId %d must be greater than the largest player Id %d", d.NextPlayerId, maxId)
+	}
+	return errors.Join(errs...)
+}
+
+// roster is the drill's Roster, or DefaultRoster if it has none.
+func (d *DrillFile) roster() *Roster {
+	if len(d.Roster.Teams) == 0 {
+		return DefaultRoster()
+	}
+	return &d.Roster
+}
+
+// Marshal validates the drill and encodes it at DrillFormatVersion.
+func (d *DrillFile) Marshal() ([]byte, error) {
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+	out := *d
+	out.Version = DrillFormatVersion
+	return json.MarshalIndent(out, "", " ")
+}
+
+// LoadDrillFile reads and parses the drill at path.
+func LoadDrillFile(path string) (*DrillFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	d, err := ParseDrill(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return d, nil
+}
+
+// SaveFile writes the drill to path.
+func (d *DrillFile) SaveFile(path string) error {
+	data, err := d.Marshal()
+	if err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return os.WriteFile(path, data, 0o644)
+}
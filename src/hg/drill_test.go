@@ -0,0 +1,124 @@
+package hg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const legacyDrill = `{
+ "NextPlayerId": 3,
+ "Frames": [
+  {
+   "Players": {
+    "Players": [
+     {"X": 10, "Y": 20, "Id": 1, "Team": 0, "Symbol": "C", "SkatePath": null},
+     {"X": 30, "Y": 40, "Id": 2, "Team": 1, "Symbol": "LD",
+      "SkatePath": {"TargetId": 2, "Points": [{"X": 50, "Y": 60}, {"X": 70, "Y": 80}]}}
+    ]
+   },
+   "DurationSeconds": 1.5
+  }
+ ]
+}`
+
+func TestParseDrillMigratesVersion1(t *testing.T) {
+	d, err := ParseDrill([]byte(legacyDrill))
+	require.NoError(t, err)
+	assert.Equal(t, DrillFormatVersion, d.Version)
+	assert.Equal(t, 3, d.NextPlayerId)
+	require.Len(t, d.Frames, 1)
+	assert.Equal(t, 1.5, d.Frames[0].DurationSeconds)
+	require.Len(t, d.Frames[0].Players.Players, 2)
+	assert.Equal(t, "LD", d.Frames[0].Players.Players[1].Symbol)
+	assert.Len(t, d.Frames[0].Players.Players[1].Path.Polyline(), 2)
+}
+
+func TestParseDrillMigratesVersion2(t *testing.T) {
+	d, err := ParseDrill([]byte(`{"Version": 2, "NextPlayerId": 3, "Frames": [{"DurationSeconds": 1, "Players": {"Players": [
+	 {"X": 30, "Y": 40, "Id": 2, "Team": 1, "Symbol": "LD",
+	  "SkatePath": {"TargetId": 2, "Points": [{"X": 0, "Y": 0}, {"X": 50, "Y": 1}, {"X": 100, "Y": 0}, {"X": 100, "Y": 100}]}}
+	]}}]}`))
+	require.NoError(t, err)
+	p := d.Frames[0].Players.Players[0]
+	require.IsType(t, &SkatePathWithRadius{}, p.Path)
+	assert.Equal(t, []SkatePoint{{X: 0, Y: 0}, {X: 100, Y: 0}, {X: 100, Y: 100}}, p.Path.(*SkatePathWithRadius).Points)
+	assert.InDelta(t, 100, p.CentreAt(1).Y, 0.01)
+
+	data, err := d.Marshal()
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"Type": "radius"`)
+	again, err := ParseDrill(data)
+	require.NoError(t, err)
+	assert.Equal(t, p.Path, again.Frames[0].Players.Players[0].Path)
+}
+
+func TestParseDrillMigratesVersion3(t *testing.T) {
+	d, err := ParseDrill([]byte(`{"Version": 3, "NextPlayerId": 4, "Frames": [{"DurationSeconds": 1, "Players": {"Players": [
+	 {"X": 0, "Y": 0, "Id": 1, "Team": 0, "Symbol": "C", "SkatePath": null, "Skating": null},
+	 {"X": 0, "Y": 0, "Id": 2, "Team": 0, "Symbol": "LW", "Skating": null,
+	  "SkatePath": {"TargetId": 2, "Points": [{"X": 0, "Y": 0}, {"X": 9, "Y": 0}], "Delay": 0.25, "Finish": 0, "Ease": "in"}},
+	 {"X": 0, "Y": 0, "Id": 3, "Team": 0, "Symbol": "RW", "Skating": null,
+	  "SkatePath": {"TargetId": 3, "Points": null, "Delay": 0, "Finish": 0.5, "Ease": ""},
+	  "SplinePath": {"TargetId": 3, "Points": [{"X": 0, "Y": 0}, {"X": 9, "Y": 9}], "Handles": [{"X": 1, "Y": 0}, {"X": 1, "Y": 0}]}}
+	]}}]}`))
+	require.NoError(t, err)
+	players := d.Frames[0].Players.Players
+	assert.Nil(t, players[0].Path)
+	require.IsType(t, &SkatePath{}, players[1].Path)
+	assert.Equal(t, SkateTiming{Delay: 0.25, Ease: EaseIn}, players[1].Timing)
+	require.IsType(t, &SplinePath{}, players[2].Path)
+	assert.Equal(t, SkateTiming{Finish: 0.5}, players[2].Timing)
+}
+
+func TestParseDrillRoundTrip(t *testing.T) {
+	d, err := ParseDrill([]byte(legacyDrill))
+	require.NoError(t, err)
+	data, err := d.Marshal()
+	require.NoError(t, err)
+	again, err := ParseDrill(data)
+	require.NoError(t, err)
+	assert.Equal(t, d, again)
+}
+
+func TestParseDrillRejectsUnknownFields(t *testing.T) {
+	_, err := ParseDrill([]byte(`{"Version": 2, "NextPlayerId": 0, "Frames": [], "Speed": 3}`))
+	assert.ErrorContains(t, err, `unknown field "Speed"`)
+
+	_, err = ParseDrill([]byte(`{"NextPlayerId": 0, "Frames": [], "Speed": 3}`))
+	assert.ErrorContains(t, err, "migrating from version 1")
+}
+
+func TestParseDrillRejectsNewerVersion(t *testing.T) {
+	_, err := ParseDrill([]byte(`{"Version": 99, "Frames": []}`))
+	assert.ErrorContains(t, err, "unsupported format version 99")
+}
+
+func TestDrillValidate(t *testing.T) {
+	d := &DrillFile{
+		NextPlayerId: 2,
+		Frames: []frame{
+			{DurationSeconds: 1, Players: &PlayerGroup{Players: []*Player{
+				{Id: 1, Symbol: "LW"},
+				{Id: 1, Symbol: "RW"},
+			}}},
+			{DurationSeconds: -2, Players: &PlayerGroup{Players: []*Player{
+				{Id: 4, Symbol: "C",
+					Path:   &SkatePathWithRadius{Points: []SkatePoint{{X: 0, Y: 0}, {X: 9, Y: 9}}, PointRadiuses: []float32{0}},
+					Timing: SkateTiming{Delay: 0.5, Finish: 0.4, Ease: "zigzag"}},
+			}}},
+		},
+	}
+	err := d.Validate()
+	require.Error(t, err)
+	assert.ErrorContains(t, err, `frame 1: duplicate player Id 1 ("LW" and "RW")`)
+	assert.ErrorContains(t, err, "frame 2: negative DurationSeconds -2")
+	assert.ErrorContains(t, err, `frame 2: player 4 ("C") has 2 radius path points but 1 PointRadiuses`)
+	assert.ErrorContains(t, err, `frame 2: player 4 ("C") has Timing Delay 0.5 and Finish 0.4`)
+	assert.ErrorContains(t, err, `frame 2: player 4 ("C") has unknown Timing Ease "zigzag"`)
+	assert.ErrorContains(t, err, "NextPlayerId 2 must be greater than the largest player Id 4")
+
+	_, err = d.Marshal()
+	assert.Error(t, err)
+}
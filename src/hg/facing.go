@@ -0,0 +1,148 @@
+package hg
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"sort"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Which side a player carries their stick, see Player.Shoots.
+const (
+	ShootsLeft  = "L"
+	ShootsRight = "R"
+)
+
+// Facing records which segments of a path, the stretches between one control
+// point and the next, are skated backwards.  Paths embed it.
+type Facing struct {
+	// Backwards[i] is true if segment i is skated backwards.  It may be
+	// shorter than the path, the rest of which is skated forwards.
+	Backwards []bool
+}
+
+// BackwardsOn reports whether segment is skated backwards.
+func (f *Facing) BackwardsOn(segment int) bool {
+	return segment >= 0 && segment < len(f.Backwards) && f.Backwards[segment]
+}
+
+// SetBackwards sets which way segment is skated.
+func (f *Facing) SetBackwards(segment int, backwards bool) {
+	if segment < 0 {
+		return
+	}
+	for len(f.Backwards) <= segment && backwards {
+		f.Backwards = append(f.Backwards, false)
+	}
+	if segment < len(f.Backwards) {
+		f.Backwards[segment] = backwards
+	}
+}
+
+// splitSegment is called when a control point is inserted at i, so that both
+// halves of the segment it splits keep its direction.
+func (f *Facing) splitSegment(i int) {
+	if i > 0 && i <= len(f.Backwards) {
+		f.Backwards = append(f.Backwards[:i:i], f.Backwards[i-1:]...)
+	}
+}
+
+// validateFacing reports more Backwards flags than segments.
+func (f *Facing) validateFacing(segments int) error {
+	if len(f.Backwards) > segments {
+		return fmt.Errorf("has %d Backwards flags for %d segments", len(f.Backwards), segments)
+	}
+	return nil
+}
+
+// controlFractions returns how far along line, as a fraction of its length,
+// it passes closest to each control point in turn.
+func controlFractions(line, controls []SkatePoint) []float32 {
+	if len(controls) < 2 {
+		return []float32{0, 1}
+	}
+	lengths := make([]float32, len(line))
+	for i := 1; i < len(line); i++ {
+		lengths[i] = lengths[i-1] + line[i].Sub(line[i-1]).Length()
+	}
+	total := lengths[len(lengths)-1]
+	fractions := make([]float32, len(controls))
+	from := 0
+	for c := 1; c < len(controls)-1; c++ {
+		best, bestDist := from, float32(math.MaxFloat32)
+		for i := from; i < len(line); i++ {
+			if d := line[i].Sub(controls[c]).LengthSq(); d < bestDist {
+				best, bestDist = i, d
+			}
+		}
+		from = best
+		if total > 0 {
+			fractions[c] = lengths[best] / total
+		}
+	}
+	fractions[len(fractions)-1] = 1
+	return fractions
+}
+
+// segmentAt returns the segment fraction falls in, given the Segments of a path.
+func segmentAt(segments []float32, fraction float32) int {
+	i := sort.Search(len(segments), func(i int) bool { return segments[i] > fraction })
+	return min(max(i-1, 0), len(segments)-2)
+}
+
+// carryFacing copies which way from is skated onto to, going by where the
+// middle of each of to's segments falls on from.
+func carryFacing(from, to Path) {
+	fs, ts := from.Segments(), to.Segments()
+	for i := 0; i+1 < len(ts); i++ {
+		to.SetBackwards(i, from.BackwardsOn(segmentAt(fs, (ts[i]+ts[i+1])/2)))
+	}
+}
+
+// facingColor draws the facing chevron and stick.
+var facingColor = color.RGBA{0x20, 0x20, 0x20, 0xff}
+
+// facingMarks returns the chevron in front of a player centred on centre and
+// heading along heading, and their stick from the top of the shaft to the toe
+// of the blade.
+func facingMarks(centre SkatePoint, heading float32, shoots string) (chevron, stick [3]SkatePoint) {
+	ahead := SkatePoint{X: float32(math.Cos(float64(heading))), Y: float32(math.Sin(float64(heading)))}
+	// Screen y points down, so this is the player's left.
+	left := SkatePoint{X: ahead.Y, Y: -ahead.X}
+	side := left
+	if shoots == ShootsRight {
+		side = left.Mul(-1)
+	}
+	tip := centre.Add(ahead.Mul(playerRadius + 8))
+	chevron = [3]SkatePoint{
+		centre.Add(ahead.Mul(playerRadius + 2)).Add(left.Mul(6)),
+		tip,
+		centre.Add(ahead.Mul(playerRadius + 2)).Add(left.Mul(-6)),
+	}
+	heel := centre.Add(side.Mul(playerRadius - 6)).Add(ahead.Mul(playerRadius + 6))
+	stick = [3]SkatePoint{
+		centre.Add(side.Mul(playerRadius - 8)),
+		heel,
+		heel.Add(side.Mul(-8)),
+	}
+	return chevron, stick
+}
+
+// drawFacing draws which way a player faces and their stick.
+func drawFacing(screen *ebiten.Image, centre SkatePoint, heading float32, shoots string, alpha float32) {
+	chevron, stick := facingMarks(centre, heading, shoots)
+	scale := func(c uint8) uint8 { return uint8(float32(c) * alpha) }
+	col := color.RGBA{scale(facingColor.R), scale(facingColor.G), scale(facingColor.B), scale(facingColor.A)}
+	for _, line := range [][3]SkatePoint{chevron, stick} {
+		vector.StrokeLine(screen, line[0].X, line[0].Y, line[1].X, line[1].Y, 2, col, true)
+		vector.StrokeLine(screen, line[1].X, line[1].Y, line[2].X, line[2].Y, 2, col, true)
+	}
+}
+
+// reverseHeading turns heading round to face the other way.
+func reverseHeading(heading float32) float32 {
+	return float32(math.Mod(float64(heading)+math.Pi, 2*math.Pi))
+}
@@ -0,0 +1,57 @@
+package hg
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFacing(t *testing.T) {
+	// Along x, then back the other way further down.
+	rp := (&SkatePathWithRadius{
+		Points:        []SkatePoint{{X: 0, Y: 0}, {X: 100, Y: 0}, {X: 100, Y: 100}, {X: 0, Y: 100}},
+		PointRadiuses: []float32{0, 0, 0, 0},
+	}).Clone()
+	assert.InDeltaSlice(t, []float32{0, 1.0 / 3, 2.0 / 3, 1}, rp.Segments(), 0.001)
+
+	p := &Player{Id: 1, Path: rp, Timing: SkateTiming{Ease: EaseLinear}}
+	assert.InDelta(t, 0, p.HeadingAt(0.1), 0.01)
+	assert.InDelta(t, math.Pi, p.HeadingAt(0.9), 0.01)
+
+	// Skating the last segment backwards faces the way they came.
+	rp.SetBackwards(2, true)
+	assert.Equal(t, []bool{false, false, true}, rp.Backwards)
+	assert.InDelta(t, 0, p.HeadingAt(0.9), 0.01)
+	require.NoError(t, rp.Validate())
+
+	// Splitting a segment keeps its direction on both halves.
+	rp.splitSegment(3)
+	assert.Equal(t, []bool{false, false, true, true}, rp.Backwards)
+	assert.ErrorContains(t, rp.Validate(), "has 4 Backwards flags for 3 segments")
+
+	// Converting a path keeps which way it is skated.
+	s := NewSplineThrough([]SkatePoint{{X: 0, Y: 0}, {X: 200, Y: 0}, {X: 200, Y: 100}})
+	s.SetBackwards(1, true)
+	freehand := &SkatePath{Points: s.Polyline()}
+	carryFacing(s, freehand)
+	assert.False(t, freehand.BackwardsOn(0))
+	rounded := s.ToRadiusPath()
+	carryFacing(s, rounded)
+	assert.Equal(t, []bool{false, true}, rounded.Backwards)
+
+	// A player standing still keeps their own Heading.
+	still := &Player{Heading: 1}
+	assert.Equal(t, float32(1), still.HeadingAt(0.5))
+}
+
+func TestFacingMarks(t *testing.T) {
+	centre := SkatePoint{X: 100, Y: 100}
+	chevron, left := facingMarks(centre, 0, "")
+	assert.Greater(t, chevron[1].X, centre.X+playerRadius)
+	// Facing along +x a left shot carries the stick on the upper side of the screen.
+	assert.Less(t, left[1].Y, centre.Y)
+	_, right := facingMarks(centre, 0, ShootsRight)
+	assert.Greater(t, right[1].Y, centre.Y)
+}
@@ -0,0 +1,65 @@
+package hg
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// ghostOptions control the translucent copies of players drawn at their
+// destinations while editing.
+type ghostOptions struct {
+	// Show draws every player at the end of its skate path.
+	Show bool
+	// NextFrame also draws the players of the next frame where they start.
+	NextFrame bool
+	// OwnTeam is drawn more strongly and ringed, the other team is fainter.
+	OwnTeam int
+}
+
+type ghostStyle struct {
+	alpha float32
+	ring  bool
+}
+
+func (o *ghostOptions) style(p *Player) ghostStyle {
+	if p.Team == o.OwnTeam {
+		return ghostStyle{alpha: 0.45, ring: true}
+	}
+	return ghostStyle{alpha: 0.25}
+}
+
+// drawGhost draws a translucent copy of p centred on centre, facing heading.
+func (o *ghostOptions) drawGhost(screen *ebiten.Image, p *Player, centre SkatePoint, heading float32) {
+	style := o.style(p)
+	ghost := NewPlayerFromPlayer(p)
+	sz := p.image.Bounds().Size()
+	ghost.X = int(centre.X) - sz.X/2
+	ghost.Y = int(centre.Y) - sz.Y/2
+	ghost.Heading = heading
+	ghost.DrawWithAlpha(screen, style.alpha)
+	if style.ring {
+		r := float32(sz.X) / 2
+		vector.StrokeCircle(screen, centre.X, centre.Y, r+2, 1.5, color.RGBA{0, 0, 0, 0x80}, true)
+	}
+}
+
+// Draw draws the ghosts for frameIndex.
+func (o *ghostOptions) Draw(screen *ebiten.Image, frames []frame, frameIndex int) {
+	if !o.Show {
+		return
+	}
+	for _, p := range frames[frameIndex].Players.Players {
+		if p.Path != nil {
+			o.drawGhost(screen, p, p.Path.Interpolate(1), p.HeadingAt(1))
+		}
+	}
+	if o.NextFrame && frameIndex+1 < len(frames) {
+		next := &frames[frameIndex+1]
+		for _, p := range next.Players.Players {
+			centre, heading := next.poseAt(p, 0)
+			o.drawGhost(screen, p, centre, heading)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package hg
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"io"
+	"math"
+	"slices"
+)
+
+// GIFOptions control animated GIF export.
+type GIFOptions struct {
+	// FPS is how many images are sampled per second of drill time.
+	FPS float64
+	// HoldSeconds is how long the final positions stay on screen before the animation loops.
+	HoldSeconds float64
+}
+
+// DefaultGIFOptions suit sharing a drill in chat.
+var DefaultGIFOptions = GIFOptions{FPS: 15, HoldSeconds: 1.5}
+
+// gifSamples returns the (frame, fraction) pairs played back at fps, using
+// each frame's DurationSeconds.  Zero length frames are skipped, and the last
+// sample is the final positions.
+func gifSamples(frames []frame, fps float64) (frameIndexes []int, fractions []float32) {
+	for i, frame := range frames {
+		n := int(math.Round(frame.DurationSeconds * fps))
+		if frame.DurationSeconds > 0 {
+			n = max(n, 1)
+		}
+		for step := 0; step < n; step++ {
+			frameIndexes = append(frameIndexes, i)
+			fractions = append(fractions, float32(step)/float32(n))
+		}
+	}
+	frameIndexes = append(frameIndexes, len(frames)-1)
+	fractions = append(fractions, 1)
+	return frameIndexes, fractions
+}
+
+// WriteGIF plays the whole drill and encodes it as a looping animated GIF.
+// Like Render it must run inside the ebiten game loop.
+func (r *DrillRenderer) WriteGIF(w io.Writer, opts GIFOptions) error {
+	if opts.FPS <= 0 {
+		opts.FPS = DefaultGIFOptions.FPS
+	}
+	delay := int(math.Round(100 / opts.FPS))
+	frameIndexes, fractions := gifSamples(r.drill.Frames, opts.FPS)
+
+	// Sample the start of every frame and the end for the palette, so
+	// colors that only appear part way through the drill are kept.
+	var samples []*image.RGBA
+	for i := range frameIndexes {
+		if i == 0 || i == len(frameIndexes)-1 || frameIndexes[i] != frameIndexes[i-1] {
+			samples = append(samples, r.Render(frameIndexes[i], fractions[i]))
+		}
+	}
+	palette := gifPalette(samples, r.drill.roster())
+
+	anim := &gif.GIF{}
+	for i := range frameIndexes {
+		img := r.Render(frameIndexes[i], fractions[i])
+		paletted := image.NewPaletted(img.Bounds(), palette)
+		draw.Draw(paletted, paletted.Bounds(), img, img.Bounds().Min, draw.Src)
+		anim.Image = append(anim.Image, paletted)
+		anim.Delay = append(anim.Delay, delay)
+	}
+	anim.Delay[len(anim.Delay)-1] += int(math.Round(opts.HoldSeconds * 100))
+	return gif.EncodeAll(w, anim)
+}
+
+// gifPalette is built for flat diagrams rather than photos: white ice, black
+// lines, the team colors, anti-aliasing ramps from the ice to each of those,
+// and the rest filled with the most common colors of the sample images,
+// which picks up the rink markings and props.
+func gifPalette(samples []*image.RGBA, roster *Roster) color.Palette {
+	const maxColors = 256
+	const rampSteps = 8
+	white := color.RGBA{0xff, 0xff, 0xff, 0xff}
+	palette := color.Palette{white}
+	add := func(c color.RGBA) {
+		if len(palette) < maxColors && !slices.Contains(palette, color.Color(c)) {
+			palette = append(palette, c)
+		}
+	}
+	ramp := func(to color.RGBA) {
+		for i := 1; i <= rampSteps; i++ {
+			add(lerpRGBA(white, to, float32(i)/rampSteps))
+		}
+	}
+
+	ramp(color.RGBA{0, 0, 0, 0xff})
+	for i := range roster.Teams {
+		team := roster.teamColor(i)
+		ramp(team)
+		// Sprite text is white on the team color.
+		add(lerpRGBA(team, white, 0.5))
+	}
+
+	// Round to steps of 8 a channel and take the most common remaining
+	// colors.  Rounding rather than truncating keeps white white.
+	quantize := func(v uint8) uint8 { return uint8(min((int(v)+4)&^7, 0xff)) }
+	counts := map[color.RGBA]int{}
+	for _, sample := range samples {
+		for i := 0; i+3 < len(sample.Pix); i += 4 {
+			c := color.RGBA{quantize(sample.Pix[i]), quantize(sample.Pix[i+1]), quantize(sample.Pix[i+2]), 0xff}
+			counts[c]++
+		}
+	}
+	common := make([]color.RGBA, 0, len(counts))
+	for c := range counts {
+		common = append(common, c)
+	}
+	// Ties are broken by color so the palette is the same every run.
+	packed := func(c color.RGBA) int { return int(c.R)<<16 | int(c.G)<<8 | int(c.B) }
+	slices.SortFunc(common, func(a, b color.RGBA) int {
+		if counts[a] != counts[b] {
+			return counts[b] - counts[a]
+		}
+		return packed(a) - packed(b)
+	})
+	for _, c := range common {
+		add(c)
+	}
+	return palette
+}
+
+func lerpRGBA(a, b color.RGBA, t float32) color.RGBA {
+	l := func(x, y uint8) uint8 {
+		return uint8(float32(x) + (float32(y)-float32(x))*t + 0.5)
+	}
+	return color.RGBA{l(a.R, b.R), l(a.G, b.G), l(a.B, b.B), l(a.A, b.A)}
+}
@@ -0,0 +1,76 @@
+package hg
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGIFSamples(t *testing.T) {
+	tests := []struct {
+		durations []float64
+		fps       float64
+		frames    []int
+		fractions []float32
+	}{
+		{[]float64{1}, 2, []int{0, 0, 0}, []float32{0, 0.5, 1}},
+		// Zero length frames are skipped, short ones get at least one sample.
+		{[]float64{0, 0.1, 0.5}, 4, []int{1, 2, 2, 2}, []float32{0, 0, 0.5, 1}},
+		{[]float64{0}, 10, []int{0}, []float32{1}},
+	}
+	for _, tt := range tests {
+		frames := make([]frame, len(tt.durations))
+		for i, d := range tt.durations {
+			frames[i].DurationSeconds = d
+		}
+		indexes, fractions := gifSamples(frames, tt.fps)
+		assert.Equal(t, tt.frames, indexes, "durations %v", tt.durations)
+		assert.Equal(t, tt.fractions, fractions, "durations %v", tt.durations)
+	}
+}
+
+func TestGIFPalette(t *testing.T) {
+	roster := DefaultRoster()
+	white := color.RGBA{0xff, 0xff, 0xff, 0xff}
+	fill := func(w, h int, c color.RGBA) *image.RGBA {
+		img := image.NewRGBA(image.Rect(0, 0, w, h))
+		for x := range w {
+			for y := range h {
+				img.SetRGBA(x, y, c)
+			}
+		}
+		return img
+	}
+
+	// White, the ramps and the team colors are always there.
+	fixed := gifPalette(nil, roster)
+	assert.Equal(t, color.Color(white), fixed[0])
+	assert.Contains(t, fixed, color.Color(color.RGBA{0, 0, 0, 0xff}))
+	assert.Contains(t, fixed, color.Color(roster.teamColor(0)))
+
+	// White ice adds nothing, but a color in any sample is kept.
+	assert.Len(t, gifPalette([]*image.RGBA{fill(4, 4, white)}, roster), len(fixed))
+	palette := gifPalette([]*image.RGBA{fill(4, 4, white), fill(2, 2, coneColor)}, roster)
+	assert.Len(t, palette, len(fixed)+1)
+	assert.Contains(t, palette, color.Color(coneColor))
+
+	// Colors as common as each other come in the same order every time.
+	even := fill(2, 1, color.RGBA{0x40, 0x80, 0x40, 0xff})
+	even.SetRGBA(1, 0, color.RGBA{0x10, 0x80, 0xc0, 0xff})
+	first := gifPalette([]*image.RGBA{even}, roster)
+	for range 10 {
+		assert.Equal(t, first, gifPalette([]*image.RGBA{even}, roster))
+	}
+	assert.Equal(t, color.Color(color.RGBA{0x10, 0x80, 0xc0, 0xff}), first[len(fixed)])
+
+	// A busy one fills the palette but never overflows it.
+	busy := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	for x := range 64 {
+		for y := range 64 {
+			busy.SetRGBA(x, y, color.RGBA{uint8(x * 4), uint8(y * 4), 0x80, 0xff})
+		}
+	}
+	assert.Len(t, gifPalette([]*image.RGBA{busy}, roster), 256)
+}
@@ -0,0 +1,120 @@
+package hg
+
+import (
+	"math"
+)
+
+// netCentres are the middles of the two goal mouths in drill pixels.
+func netCentres() [2]SkatePoint {
+	nets := activeRink.Rink.NetCentres()
+	return [2]SkatePoint{activeRink.ToPixels(nets[0]), activeRink.ToPixels(nets[1])}
+}
+
+const (
+	// goalieDepth is how far out from the goal line a tracking goalie
+	// plays in metres, inside the 1.83m crease.
+	goalieDepth = 1.2
+	// goalieMaxAngle is how far round from straight out a goalie turns to
+	// follow the puck towards the boards, in radians.
+	goalieMaxAngle = 80 * math.Pi / 180
+)
+
+// GoalieTracking keeps a goalie in their crease, squared up to the puck or
+// to Target, in place of skating a Path.
+type GoalieTracking struct {
+	// Net is the goal they tend, 0 for the left and 1 for the right.
+	Net int
+	// Target is what they square up to, or nil to follow the puck.
+	Target *SkatePoint
+}
+
+// nearestNet is the net closest to pt.
+func nearestNet(pt SkatePoint) int {
+	nets := netCentres()
+	if pt.Sub(nets[1]).LengthSq() < pt.Sub(nets[0]).LengthSq() {
+		return 1
+	}
+	return 0
+}
+
+// Clone returns a copy with its own Target.
+func (t *GoalieTracking) Clone() *GoalieTracking {
+	if t == nil {
+		return nil
+	}
+	c := *t
+	if t.Target != nil {
+		target := *t.Target
+		c.Target = &target
+	}
+	return &c
+}
+
+// outAngle is the heading from the net to centre ice.
+func (t *GoalieTracking) outAngle() float64 {
+	if t.Net == 1 {
+		return math.Pi
+	}
+	return 0
+}
+
+// restTarget is Target, or straight out from the net when following a puck
+// that can't be placed, such as when the goalie has it.
+func (t *GoalieTracking) restTarget() SkatePoint {
+	if t.Target != nil {
+		return *t.Target
+	}
+	a := t.outAngle()
+	return netCentres()[t.Net].Add(SkatePoint{X: float32(math.Cos(a)), Y: float32(math.Sin(a))}.Mul(10 * activeRink.Scale))
+}
+
+// pose is where the goalie stands and the way they face when squared up to
+// target: on the line from the middle of the net to target, goalieDepth out,
+// turned no further round than goalieMaxAngle.
+func (t *GoalieTracking) pose(target SkatePoint) (SkatePoint, float32) {
+	net := netCentres()[t.Net]
+	out := t.outAngle()
+	a := out
+	if d := target.Sub(net); d.LengthSq() > 0 {
+		a = out + math.Remainder(float64(d.Heading())-out, 2*math.Pi)
+	}
+	a = min(max(a, out-goalieMaxAngle), out+goalieMaxAngle)
+	dir := SkatePoint{X: float32(math.Cos(a)), Y: float32(math.Sin(a))}
+	return net.Add(dir.Mul(goalieDepth * activeRink.Scale)), float32(math.Mod(a+2*math.Pi, 2*math.Pi))
+}
+
+// tracking returns the player's GoalieTracking if they are a goalie using it.
+func (p *Player) tracking() *GoalieTracking {
+	if p.Role != RoleGoalie {
+		return nil
+	}
+	return p.Tracking
+}
+
+// poseAt is where p is and the way they face at fraction of f, with tracking
+// goalies squared up to the puck.
+func (f *frame) poseAt(p *Player, fraction float32) (SkatePoint, float32) {
+	t := p.tracking()
+	if t == nil {
+		return p.CentreAt(fraction), p.HeadingAt(fraction)
+	}
+	target := t.restTarget()
+	if t.Target == nil && f.Puck != nil {
+		if pos, holder := f.Puck.Position(f.Players, fraction); holder != p.Id {
+			target = pos
+		}
+	}
+	return t.pose(target)
+}
+
+// Interpolate moves every player in f to where they are at fraction.
+func (f *frame) Interpolate(fraction float32) {
+	f.Players.Interpolate(fraction)
+	for _, p := range f.Players.Players {
+		if p.tracking() != nil {
+			centre, heading := f.poseAt(p, fraction)
+			p.moveCentreTo(centre)
+			p.Heading = heading
+		}
+	}
+}
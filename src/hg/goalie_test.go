@@ -0,0 +1,82 @@
+package hg
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGoalieTracking(t *testing.T) {
+	left := &GoalieTracking{Net: 0}
+	net := netCentres()[0]
+
+	// Straight out to a puck in front, and round towards one to the side.
+	centre, heading := left.pose(net.Add(SkatePoint{X: 200, Y: 0}))
+	assert.InDelta(t, net.X+goalieDepth*activeRink.Scale, centre.X, 0.01)
+	assert.InDelta(t, net.Y, centre.Y, 0.01)
+	assert.InDelta(t, 0, heading, 0.01)
+	centre, _ = left.pose(net.Add(SkatePoint{X: 100, Y: 100}))
+	assert.InDelta(t, goalieDepth*activeRink.Scale, centre.Sub(net).Length(), 0.01)
+	assert.InDelta(t, centre.X-net.X, centre.Y-net.Y, 0.01)
+
+	// A puck behind the goal line only turns them as far as goalieMaxAngle.
+	_, heading = left.pose(net.Add(SkatePoint{X: -50, Y: 10}))
+	assert.InDelta(t, goalieMaxAngle, heading, 0.01)
+
+	right := &GoalieTracking{Net: 1}
+	_, heading = right.pose(SkatePoint{X: 640, Y: netCentres()[1].Y})
+	assert.InDelta(t, math.Pi, heading, 0.01)
+	assert.Equal(t, 1, nearestNet(SkatePoint{X: 1000, Y: 100}))
+
+	// Following the puck as it is passed across the ice.
+	goalie := &Player{Id: 1, Role: RoleGoalie, Tracking: &GoalieTracking{Net: 0}}
+	f := &frame{
+		Players: &PlayerGroup{Players: []*Player{goalie}},
+		Puck: &Puck{CarrierId: -1, X: 400, Y: 100, Moves: []PuckMove{
+			{Kind: PuckShot, Target: SkatePoint{X: 400, Y: 500}, Start: 0, End: 1},
+		}},
+	}
+	start, _ := f.poseAt(goalie, 0)
+	end, _ := f.poseAt(goalie, 1)
+	assert.Less(t, start.Y, net.Y)
+	assert.Greater(t, end.Y, net.Y)
+	// Without the puck they face straight out.
+	rest, _ := goalie.tracking().pose(goalie.tracking().restTarget())
+	assert.Equal(t, rest, goalie.CentreAt(0.5))
+
+	data, err := json.Marshal(goalie)
+	require.NoError(t, err)
+	again := &Player{}
+	require.NoError(t, json.Unmarshal(data, again))
+	assert.Equal(t, goalie.Tracking, again.Tracking)
+	assert.Equal(t, RoleGoalie, again.Role)
+
+	d := &DrillFile{NextPlayerId: 5, Frames: []frame{{DurationSeconds: 1, Players: &PlayerGroup{Players: []*Player{
+		{Id: 1, Symbol: "G", Role: RoleGoalie, Tracking: &GoalieTracking{Net: 2}},
+		{Id: 2, Symbol: "LW", Tracking: &GoalieTracking{}},
+		{Id: 3, Symbol: "G", Role: RoleGoalie, Tracking: &GoalieTracking{}, Path: &SkatePath{Points: []SkatePoint{{}, {X: 9}}}},
+		{Id: 4, Symbol: "Z", Role: "zamboni"},
+	}}}}}
+	err = d.Validate()
+	assert.ErrorContains(t, err, `frame 1: player 1 ("G") has Tracking Net 2, want 0 or 1`)
+	assert.ErrorContains(t, err, `frame 1: player 2 ("LW") has Tracking but is not a goalie`)
+	assert.ErrorContains(t, err, `frame 1: player 3 ("G") has both a Path and Tracking`)
+	assert.ErrorContains(t, err, `frame 1: player 4 ("Z") has unknown Role "zamboni"`)
+}
+
+func TestRoleOutline(t *testing.T) {
+	centre := SkatePoint{X: 50, Y: 50}
+	assert.Nil(t, roleOutline(RoleSkater, centre, playerRadius))
+	for _, role := range Roles[1:] {
+		outline := roleOutline(role, centre, playerRadius)
+		require.NotEmpty(t, outline, role.String())
+		for _, p := range outline {
+			// Inside the sprite image.
+			d := p.Sub(centre)
+			assert.True(t, max(d.X, -d.X, d.Y, -d.Y) <= playerRadius, "%s point %v is off its sprite", role, p)
+		}
+	}
+}
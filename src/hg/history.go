@@ -0,0 +1,194 @@
+package hg
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/inpututil"
+)
+
+// Command is an edit that can be undone and redone.
+type Command interface {
+	Name() string
+	Undo(g *Game)
+	Redo(g *Game)
+}
+
+// History is a bounded undo/redo stack of Commands.
+type History struct {
+	// Limit is the most commands kept; the oldest are forgotten first.
+	Limit int
+	undo  []Command
+	redo  []Command
+}
+
+// Push records a command that has just been done, clearing the redo stack.
+func (h *History) Push(c Command) {
+	h.undo = append(h.undo, c)
+	if h.Limit > 0 && len(h.undo) > h.Limit {
+		h.undo = h.undo[len(h.undo)-h.Limit:]
+	}
+	h.redo = nil
+}
+
+// Undo undoes the most recent command and returns it, or nil if there is none.
+func (h *History) Undo(g *Game) Command {
+	if len(h.undo) == 0 {
+		return nil
+	}
+	c := h.undo[len(h.undo)-1]
+	h.undo = h.undo[:len(h.undo)-1]
+	c.Undo(g)
+	h.redo = append(h.redo, c)
+	return c
+}
+
+// Redo redoes the most recently undone command and returns it, or nil if there is none.
+func (h *History) Redo(g *Game) Command {
+	if len(h.redo) == 0 {
+		return nil
+	}
+	c := h.redo[len(h.redo)-1]
+	h.redo = h.redo[:len(h.redo)-1]
+	c.Redo(g)
+	h.undo = append(h.undo, c)
+	return c
+}
+
+// last returns the most recent command, or nil.
+func (h *History) last() Command {
+	if len(h.undo) == 0 {
+		return nil
+	}
+	return h.undo[len(h.undo)-1]
+}
+
+// Clear forgets every command.
+func (h *History) Clear() {
+	h.undo = nil
+	h.redo = nil
+}
+
+// drillSnapshot is a deep copy of everything an edit can change.
+type drillSnapshot struct {
+	frames           []frame
+	nextPlayerId     int
+	activeFrameIndex int
+	rinkLayout       RinkLayout
+}
+
+func cloneFrames(frames []frame) []frame {
+	ret := make([]frame, len(frames))
+	for i, f := range frames {
+		ret[i] = f
+		ret[i].Players = f.Players.Clone()
+		ret[i].Puck = f.Puck.Clone()
+		ret[i].Props = cloneProps(f.Props)
+	}
+	return ret
+}
+
+func (g *Game) snapshot() drillSnapshot {
+	return drillSnapshot{
+		frames:           cloneFrames(g.frames),
+		nextPlayerId:     g.nextPlayerId,
+		activeFrameIndex: g.activeFrameIndex,
+		rinkLayout:       g.rinkLayout.clone(),
+	}
+}
+
+// restore puts the editor back to s.  s is copied so it can be restored again.
+func (g *Game) restore(s drillSnapshot) {
+	g.frames = cloneFrames(s.frames)
+	g.nextPlayerId = s.nextPlayerId
+	g.activeFrameIndex = min(s.activeFrameIndex, len(g.frames)-1)
+	if g.rinkLayout != s.rinkLayout {
+		g.setRinkLayout(s.rinkLayout.clone())
+	}
+	g.activeDragPlayer = nil
+	g.activeDragProp = nil
+	g.activeSkatePath = nil
+	g.playback.Playing = false
+}
+
+// snapshotCommand undoes and redoes an edit by restoring the whole drill to
+// how it was before or after.  Drills are small, so this is cheap, and it
+// covers every kind of edit without each needing its own inverse.
+type snapshotCommand struct {
+	name          string
+	before, after drillSnapshot
+}
+
+func (c *snapshotCommand) Name() string { return c.name }
+func (c *snapshotCommand) Undo(g *Game) { g.restore(c.before) }
+func (c *snapshotCommand) Redo(g *Game) { g.restore(c.after) }
+
+// recordEdit runs f as a single undoable command.
+func (g *Game) recordEdit(name string, f func()) {
+	before := g.snapshot()
+	f()
+	g.history.Push(&snapshotCommand{name: name, before: before, after: g.snapshot()})
+}
+
+// recordFieldEdit is recordEdit for widgets that change a value a little at a
+// time.  An edit straight after one with the same name is merged into it, so
+// dragging a number field undoes in one step.
+func (g *Game) recordFieldEdit(name string, f func()) {
+	if last, ok := g.history.last().(*snapshotCommand); ok && last.name == name && len(g.history.redo) == 0 {
+		f()
+		last.after = g.snapshot()
+		return
+	}
+	g.recordEdit(name, f)
+}
+
+// beginDragEdit remembers the drill as a drag starts.  The drag becomes an
+// undoable command when it ends if something named it with nameDragEdit.
+func (g *Game) beginDragEdit() {
+	before := g.snapshot()
+	g.pendingEdit = &snapshotCommand{before: before}
+}
+
+// nameDragEdit marks the drag in progress as an edit called name.
+func (g *Game) nameDragEdit(name string) {
+	if g.pendingEdit != nil {
+		g.pendingEdit.name = name
+	}
+}
+
+// endDragEdit records the finished drag if it edited anything.
+func (g *Game) endDragEdit() {
+	if g.pendingEdit != nil && g.pendingEdit.name != "" {
+		g.pendingEdit.after = g.snapshot()
+		g.history.Push(g.pendingEdit)
+	}
+	g.pendingEdit = nil
+}
+
+func (g *Game) Undo() {
+	if c := g.history.Undo(g); c != nil {
+		g.status = "Undid " + c.Name()
+	}
+}
+
+func (g *Game) Redo() {
+	if c := g.history.Redo(g); c != nil {
+		g.status = "Redid " + c.Name()
+	}
+}
+
+// handleUndoKeys binds Ctrl+Z to undo and Ctrl+Y or Ctrl+Shift+Z to redo.
+// Cmd works in place of Ctrl.
+func (g *Game) handleUndoKeys() {
+	if g.pendingEdit != nil {
+		return
+	}
+	if !ebiten.IsKeyPressed(ebiten.KeyControl) && !ebiten.IsKeyPressed(ebiten.KeyMeta) {
+		return
+	}
+	shift := ebiten.IsKeyPressed(ebiten.KeyShift)
+	switch {
+	case inpututil.IsKeyJustPressed(ebiten.KeyZ) && !shift:
+		g.Undo()
+	case inpututil.IsKeyJustPressed(ebiten.KeyY), inpututil.IsKeyJustPressed(ebiten.KeyZ) && shift:
+		g.Redo()
+	}
+}
@@ -0,0 +1,100 @@
+package hg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistory(t *testing.T) {
+	g := &Game{
+		history: History{Limit: 3},
+		frames:  []frame{{Players: &PlayerGroup{}, DurationSeconds: 1}},
+	}
+	addPlayer := func(id int) {
+		g.recordEdit("Add player", func() {
+			g.activeFrame().Players.Add(&Player{Id: id})
+			g.nextPlayerId = id + 1
+		})
+	}
+
+	// Only the last Limit edits are kept.
+	for id := range 5 {
+		addPlayer(id)
+	}
+	assert.Len(t, g.history.undo, 3)
+	for range 4 {
+		g.Undo()
+	}
+	assert.Len(t, g.activeFrame().Players.Players, 2)
+	assert.Equal(t, 2, g.nextPlayerId)
+
+	// Redo puts the edits back, and undo takes them away again.
+	g.Redo()
+	g.Redo()
+	assert.Len(t, g.activeFrame().Players.Players, 4)
+	assert.Equal(t, "Redid Add player", g.status)
+	g.Undo()
+	assert.Len(t, g.activeFrame().Players.Players, 3)
+	assert.Len(t, g.history.redo, 2)
+
+	// A new edit forgets what could be redone.
+	addPlayer(9)
+	assert.Empty(t, g.history.redo)
+	assert.Nil(t, g.history.Redo(g))
+
+	// Edits to a field with the same name merge into one.
+	g.history.Limit = 10
+	before := len(g.history.undo)
+	g.recordFieldEdit("Duration", func() { g.activeFrame().DurationSeconds = 2 })
+	g.recordFieldEdit("Duration", func() { g.activeFrame().DurationSeconds = 3 })
+	assert.Len(t, g.history.undo, before+1)
+	g.recordFieldEdit("Other", func() {})
+	assert.Len(t, g.history.undo, before+2)
+	g.Undo()
+	g.Undo()
+	assert.Equal(t, 1.0, g.activeFrame().DurationSeconds)
+	g.Redo()
+	assert.Equal(t, 3.0, g.activeFrame().DurationSeconds)
+}
+
+func TestDragEdit(t *testing.T) {
+	g := &Game{frames: []frame{{Players: &PlayerGroup{}, DurationSeconds: 1}}}
+
+	// A drag nothing named is not recorded.
+	g.beginDragEdit()
+	g.endDragEdit()
+	assert.Nil(t, g.history.last())
+	assert.Nil(t, g.pendingEdit)
+
+	// A named drag is, under its last name.
+	g.beginDragEdit()
+	g.nameDragEdit("Add player")
+	g.activeFrame().Players.Add(&Player{Id: 1})
+	g.nameDragEdit("Move player")
+	g.endDragEdit()
+	assert.Equal(t, "Move player", g.history.last().Name())
+	g.Undo()
+	assert.Empty(t, g.activeFrame().Players.Players)
+	assert.Equal(t, "Undid Move player", g.status)
+
+	// Naming with no drag in progress does nothing.
+	g.nameDragEdit("Stray")
+	assert.Nil(t, g.pendingEdit)
+}
+
+func TestRestoreRink(t *testing.T) {
+	old := activeRink
+	defer useRink(old)
+	g := &Game{frames: []frame{{Players: &PlayerGroup{}, DurationSeconds: 1}}}
+	g.setRinkLayout(RinkLayout{})
+
+	g.recordEdit("Rink size", func() { g.setRinkLayout(RinkLayout{Size: RinkIIHF, Area: RinkHalf}) })
+	assert.Equal(t, RinkIIHF, g.rinkLayout.Size)
+	g.Undo()
+	assert.Equal(t, RinkLayout{}, g.rinkLayout)
+	assert.Equal(t, RinkLayout{}.View().Rink, activeRink.Rink)
+	g.Redo()
+	assert.Equal(t, RinkLayout{Size: RinkIIHF, Area: RinkHalf}, g.rinkLayout)
+	assert.Equal(t, float32(60), activeRink.Rink.Length)
+}
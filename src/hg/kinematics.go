@@ -0,0 +1,175 @@
+package hg
+
+import (
+	"math"
+	"sort"
+)
+
+// SkaterLimits are how hard a player can skate, in metres and seconds.
+type SkaterLimits struct {
+	MaxSpeed float32
+	// Accel is how quickly they pick up speed and Brake how quickly they stop.
+	Accel float32
+	Brake float32
+	// MaxTurnAccel limits cornering speed to sqrt(MaxTurnAccel * radius).
+	MaxTurnAccel float32
+}
+
+// DefaultSkaterLimits are used for players without their own.
+var DefaultSkaterLimits = SkaterLimits{MaxSpeed: 8, Accel: 3, Brake: 6, MaxTurnAccel: 5}
+
+// trajectoryStep is the spacing in pixels a path is sampled at for speed planning.
+const trajectoryStep = 4
+
+// trajectoryTurnSpan is how many steps either side of a sample its turn is
+// measured over, so hand drawn wobbles don't read as tight corners.
+const trajectoryTurnSpan = 3
+
+// Trajectory is the time a skater takes along a path.  Players start and end
+// each path at rest, speed up and slow down within their SkaterLimits and
+// ease off for corners.  Times are fractions of the whole skate, so the
+// skate is stretched or squeezed to fill its frame but keeps its shape.
+type Trajectory struct {
+	// times[i] is when the skater is lengths[i] of the way along the path.
+	times   []float32
+	lengths []float32
+	// Seconds is how long the skate takes at the limits.
+	Seconds float32
+}
+
+// NewTrajectory plans skating points at limits.
+func NewTrajectory(points []SkatePoint, limits SkaterLimits) *Trajectory {
+	sp := &SkatePath{Points: points}
+	total := sp.TotalLength()
+	steps := int(math.Ceil(float64(total / trajectoryStep)))
+	if steps < 2 || limits.MaxSpeed <= 0 || limits.Accel <= 0 || limits.Brake <= 0 {
+		return nil
+	}
+	ds := total / float32(steps) / activeRink.Scale
+	samples := make([]SkatePoint, steps+1)
+	for i := range samples {
+		samples[i] = sp.Interpolate(float32(i) / float32(steps))
+	}
+
+	// Fastest speed at each sample, first for cornering, then speeding up
+	// from rest, then slowing down to stop at the end.
+	speeds := make([]float64, steps+1)
+	for i := range speeds {
+		speeds[i] = float64(limits.MaxSpeed)
+		if turn := turnAt(samples, i); turn > 0 && limits.MaxTurnAccel > 0 {
+			radius := float64(trajectoryTurnSpan*ds) / turn
+			speeds[i] = math.Min(speeds[i], math.Sqrt(float64(limits.MaxTurnAccel)*radius))
+		}
+	}
+	speeds[0], speeds[steps] = 0, 0
+	for i := 1; i <= steps; i++ {
+		speeds[i] = math.Min(speeds[i], math.Sqrt(speeds[i-1]*speeds[i-1]+2*float64(limits.Accel*ds)))
+	}
+	for i := steps - 1; i >= 0; i-- {
+		speeds[i] = math.Min(speeds[i], math.Sqrt(speeds[i+1]*speeds[i+1]+2*float64(limits.Brake*ds)))
+	}
+
+	t := &Trajectory{times: make([]float32, steps+1), lengths: make([]float32, steps+1)}
+	var seconds float64
+	for i := 1; i <= steps; i++ {
+		seconds += 2 * float64(ds) / (speeds[i-1] + speeds[i])
+		t.times[i] = float32(seconds)
+		t.lengths[i] = float32(i) / float32(steps)
+	}
+	for i := range t.times {
+		t.times[i] /= float32(seconds)
+	}
+	t.Seconds = float32(seconds)
+	return t
+}
+
+// turnAt is how far in radians the path turns over the steps around sample i.
+func turnAt(samples []SkatePoint, i int) float64 {
+	before, after := max(0, i-trajectoryTurnSpan), min(len(samples)-1, i+trajectoryTurnSpan)
+	in, out := samples[i].Sub(samples[before]), samples[after].Sub(samples[i])
+	if in.LengthSq() == 0 || out.LengthSq() == 0 {
+		return 0
+	}
+	turn := math.Abs(float64(out.Heading() - in.Heading()))
+	return math.Min(turn, 2*math.Pi-turn)
+}
+
+// LengthFraction is how far along the path the skater is at time fraction.
+func (t *Trajectory) LengthFraction(fraction float32) float32 {
+	if t == nil {
+		return fraction
+	}
+	i := sort.Search(len(t.times), func(i int) bool { return t.times[i] >= fraction })
+	if i == 0 {
+		return 0
+	}
+	if i == len(t.times) {
+		return 1
+	}
+	span := t.times[i] - t.times[i-1]
+	return t.lengths[i-1] + (fraction-t.times[i-1])/span*(t.lengths[i]-t.lengths[i-1])
+}
+
+// EaseCurve is how a player's speed changes along their skate path.
+type EaseCurve string
+
+const (
+	// EaseSkate plans speed from the player's SkaterLimits, see Trajectory.
+	EaseSkate  EaseCurve = ""
+	EaseLinear EaseCurve = "linear"
+	EaseIn     EaseCurve = "in"
+	EaseOut    EaseCurve = "out"
+	EaseInOut  EaseCurve = "inout"
+)
+
+// EaseCurves lists every curve in the order the editor offers them.
+var EaseCurves = []EaseCurve{EaseSkate, EaseLinear, EaseIn, EaseOut, EaseInOut}
+
+func (e EaseCurve) String() string {
+	if e == EaseSkate {
+		return "skate"
+	}
+	return string(e)
+}
+
+// Apply maps a time fraction to a length fraction.  EaseSkate is linear
+// here; Player.pathFraction plans it with a Trajectory instead.
+func (e EaseCurve) Apply(f float32) float32 {
+	switch e {
+	case EaseIn:
+		return f * f
+	case EaseOut:
+		return 1 - (1-f)*(1-f)
+	case EaseInOut:
+		return f * f * (3 - 2*f)
+	}
+	return f
+}
+
+// SkateTiming is when during the frame a player skates their path.
+type SkateTiming struct {
+	// Delay is the fraction of the frame the player waits before setting off.
+	Delay float32
+	// Finish is the fraction of the frame they arrive by, 0 for the end of the frame.
+	Finish float32
+	// Ease is how they speed up and slow down between Delay and Finish.
+	Ease EaseCurve
+}
+
+// finish is Finish, or the end of the frame if it is unset.
+func (t SkateTiming) finish() float32 {
+	if t.Finish <= 0 {
+		return 1
+	}
+	return t.Finish
+}
+
+// skateFraction is how far through the skate the player is at fraction of
+// the frame, 0 until Delay and 1 from Finish on.
+func (t SkateTiming) skateFraction(fraction float32) float32 {
+	start, end := t.Delay, t.finish()
+	if end <= start {
+		return 1
+	}
+	return min(1, max(0, (fraction-start)/(end-start)))
+}
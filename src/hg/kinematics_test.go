@@ -0,0 +1,77 @@
+package hg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrajectory(t *testing.T) {
+	straight := NewTrajectory([]SkatePoint{{X: 0, Y: 0}, {X: 800, Y: 0}}, DefaultSkaterLimits)
+	require.NotNil(t, straight)
+	assert.Equal(t, float32(0), straight.LengthFraction(0))
+	assert.Equal(t, float32(1), straight.LengthFraction(1))
+	// Starting from rest the first half of the time covers less than half
+	// the distance, and braking is harder than accelerating.
+	assert.Less(t, straight.LengthFraction(0.5), float32(0.5))
+	assert.Greater(t, straight.LengthFraction(0.9), float32(0.9))
+	prev := float32(0)
+	for i := 1; i <= 20; i++ {
+		f := straight.LengthFraction(float32(i) / 20)
+		assert.GreaterOrEqual(t, f, prev)
+		prev = f
+	}
+
+	// The same distance with a hairpin in the middle takes longer.
+	hairpin := NewTrajectory([]SkatePoint{{X: 0, Y: 0}, {X: 400, Y: 0}, {X: 0, Y: 1}}, DefaultSkaterLimits)
+	require.NotNil(t, hairpin)
+	assert.Greater(t, hairpin.Seconds, straight.Seconds+1)
+
+	assert.Nil(t, NewTrajectory([]SkatePoint{{X: 0, Y: 0}}, DefaultSkaterLimits))
+	assert.Equal(t, float32(0.3), (*Trajectory)(nil).LengthFraction(0.3))
+}
+
+func TestSkateTiming(t *testing.T) {
+	p := &Player{
+		Id:     1,
+		Path:   &SkatePath{Points: []SkatePoint{{X: 0, Y: 0}, {X: 100, Y: 0}}},
+		Timing: SkateTiming{Delay: 0.2, Finish: 0.6, Ease: EaseLinear},
+	}
+	assert.Equal(t, SkatePoint{X: 0, Y: 0}, p.CentreAt(0.1))
+	assert.InDelta(t, 50, p.CentreAt(0.4).X, 0.01)
+	assert.Equal(t, SkatePoint{X: 100, Y: 0}, p.CentreAt(0.8))
+
+	p.Timing.Ease = EaseIn
+	assert.InDelta(t, 25, p.CentreAt(0.4).X, 0.01)
+	p.Timing.Ease = EaseOut
+	assert.InDelta(t, 75, p.CentreAt(0.4).X, 0.01)
+
+	// An unset Finish is the end of the frame.
+	p.Timing.Finish = 0
+	p.Timing.Ease = EaseLinear
+	assert.InDelta(t, 50, p.CentreAt(0.6).X, 0.01)
+}
+
+func TestSkateSeconds(t *testing.T) {
+	p := &Player{Path: &SkatePath{Points: []SkatePoint{{X: 0, Y: 0}, {X: 800, Y: 0}}}}
+	need, have := p.skateSeconds(10)
+	assert.Equal(t, p.skateTrack().trajectory.Seconds, need)
+	assert.Equal(t, float32(10), have)
+	assert.Less(t, need, have)
+
+	// Squeezing the skate into part of a short frame asks too much.
+	p.Timing = SkateTiming{Delay: 0.5}
+	need, have = p.skateSeconds(2)
+	assert.Equal(t, float32(1), have)
+	assert.Greater(t, need, have)
+
+	// Slower skaters need longer for the same path.
+	slow := &Player{Path: p.Path, Skating: &SkaterLimits{MaxSpeed: 2, Accel: 1, Brake: 2}}
+	slowNeed, _ := slow.skateSeconds(2)
+	assert.Greater(t, slowNeed, need)
+
+	need, have = (&Player{}).skateSeconds(2)
+	assert.Zero(t, need)
+	assert.Zero(t, have)
+}
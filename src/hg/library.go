@@ -0,0 +1,225 @@
+package hg
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultLibraryDir is the directory the editor keeps named drills in.
+const DefaultLibraryDir = "drills"
+
+const drillFileExt = ".json"
+
+// DrillMeta describes a drill for browsing and searching.
+type DrillMeta struct {
+	Title    string
+	Author   string
+	Tags     []string
+	AgeGroup string
+	Created  time.Time
+	Modified time.Time
+}
+
+// DrillLibrary is a directory of named drills, one file per drill.
+// A drill's name is its file name without the extension.
+type DrillLibrary struct {
+	Dir string
+	// now, if set, replaces time.Now so tests get stable timestamps.
+	now func() time.Time
+}
+
+// DrillEntry is a drill found in the library.  Err is set when the file
+// exists but could not be read, so broken drills still show up in listings.
+type DrillEntry struct {
+	Name string
+	Meta DrillMeta
+	Err  error
+}
+
+// NewDrillLibrary opens the library at dir, creating the directory if needed.
+func NewDrillLibrary(dir string) (*DrillLibrary, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &DrillLibrary{Dir: dir}, nil
+}
+
+// Path returns the file a drill name is stored in.
+func (l *DrillLibrary) Path(name string) (string, error) {
+	if err := validateDrillName(name); err != nil {
+		return "", err
+	}
+	return filepath.Join(l.Dir, name+drillFileExt), nil
+}
+
+func validateDrillName(name string) error {
+	switch {
+	case strings.TrimSpace(name) == "":
+		return errors.New("drill name is empty")
+	case strings.ContainsAny(name, `/\:`):
+		return fmt.Errorf("drill name %q must not contain path separators", name)
+	case strings.HasPrefix(name, "."):
+		return fmt.Errorf("drill name %q must not start with '.'", name)
+	}
+	return nil
+}
+
+// Exists reports whether a drill called name is in the library.
+func (l *DrillLibrary) Exists(name string) bool {
+	path, err := l.Path(name)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// List returns every drill in the library sorted by name.
+func (l *DrillLibrary) List() ([]DrillEntry, error) {
+	dirEntries, err := os.ReadDir(l.Dir)
+	if err != nil {
+		return nil, err
+	}
+	var entries []DrillEntry
+	for _, de := range dirEntries {
+		if de.IsDir() || filepath.Ext(de.Name()) != drillFileExt {
+			continue
+		}
+		entry := DrillEntry{Name: strings.TrimSuffix(de.Name(), drillFileExt)}
+		d, err := LoadDrillFile(filepath.Join(l.Dir, de.Name()))
+		if err != nil {
+			entry.Err = err
+		} else {
+			entry.Meta = d.Meta
+		}
+		entries = append(entries, entry)
+	}
+	slices.SortFunc(entries, func(a, b DrillEntry) int {
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	})
+	return entries, nil
+}
+
+// Search lists the drills matching query, as filterDrills.
+func (l *DrillLibrary) Search(query string) ([]DrillEntry, error) {
+	entries, err := l.List()
+	if err != nil {
+		return nil, err
+	}
+	return filterDrills(entries, query), nil
+}
+
+// filterDrills returns the entries matching every word of query.  A word
+// matches if it appears, ignoring case, in the name, title, author, age group
+// or a tag.
+func filterDrills(entries []DrillEntry, query string) []DrillEntry {
+	words := strings.Fields(strings.ToLower(query))
+	var matched []DrillEntry
+	for _, e := range entries {
+		haystack := strings.ToLower(strings.Join(append([]string{
+			e.Name, e.Meta.Title, e.Meta.Author, e.Meta.AgeGroup}, e.Meta.Tags...), "\n"))
+		if !slices.ContainsFunc(words, func(w string) bool { return !strings.Contains(haystack, w) }) {
+			matched = append(matched, e)
+		}
+	}
+	return matched
+}
+
+// Open loads the named drill.
+func (l *DrillLibrary) Open(name string) (*DrillFile, error) {
+	path, err := l.Path(name)
+	if err != nil {
+		return nil, err
+	}
+	return LoadDrillFile(path)
+}
+
+// Save writes d as the named drill, stamping its created and modified times.
+// An empty title defaults to the name.
+func (l *DrillLibrary) Save(name string, d *DrillFile) error {
+	path, err := l.Path(name)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if l.now != nil {
+		now = l.now()
+	}
+	if d.Meta.Created.IsZero() {
+		d.Meta.Created = now
+	}
+	d.Meta.Modified = now
+	if d.Meta.Title == "" {
+		d.Meta.Title = name
+	}
+	return d.SaveFile(path)
+}
+
+// Rename renames a drill.  It fails rather than overwrite an existing drill.
+// A title left at the old name follows it, as with Duplicate.  If the title
+// can't be updated the drill is still renamed, and the error says so.
+func (l *DrillLibrary) Rename(name, newName string) error {
+	from, to, err := l.fromTo(name, newName)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(from, to); err != nil {
+		return err
+	}
+	d, err := LoadDrillFile(to)
+	if err == nil && d.Meta.Title == name {
+		d.Meta.Title = newName
+		err = d.SaveFile(to)
+	}
+	if err != nil {
+		return fmt.Errorf("renamed %s to %s but could not update its title: %w", name, newName, err)
+	}
+	return nil
+}
+
+// Duplicate copies a drill to newName as a new drill with fresh timestamps.
+func (l *DrillLibrary) Duplicate(name, newName string) error {
+	if _, _, err := l.fromTo(name, newName); err != nil {
+		return err
+	}
+	d, err := l.Open(name)
+	if err != nil {
+		return err
+	}
+	d.Meta.Created = time.Time{}
+	if d.Meta.Title == name {
+		d.Meta.Title = newName
+	}
+	return l.Save(newName, d)
+}
+
+// Delete removes a drill from the library.
+func (l *DrillLibrary) Delete(name string) error {
+	path, err := l.Path(name)
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+func (l *DrillLibrary) fromTo(name, newName string) (from, to string, err error) {
+	if from, err = l.Path(name); err != nil {
+		return "", "", err
+	}
+	if to, err = l.Path(newName); err != nil {
+		return "", "", err
+	}
+	if _, err := os.Stat(from); err != nil {
+		return "", "", err
+	}
+	if _, err := os.Stat(to); !errors.Is(err, fs.ErrNotExist) {
+		return "", "", fmt.Errorf("drill %q already exists", newName)
+	}
+	return from, to, nil
+}
@@ -0,0 +1,72 @@
+package hg
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testDrill() *DrillFile {
+	return &DrillFile{
+		NextPlayerId: 1,
+		Frames: []frame{{DurationSeconds: 1, Players: &PlayerGroup{Players: []*Player{
+			{Id: 0, Symbol: "C"},
+		}}}},
+	}
+}
+
+func TestDrillLibrary(t *testing.T) {
+	lib, err := NewDrillLibrary(t.TempDir())
+	require.NoError(t, err)
+	stamp := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	lib.now = func() time.Time { return stamp }
+
+	d := testDrill()
+	d.Meta.Author = "Sam"
+	d.Meta.Tags = []string{"Breakout", "rush"}
+	require.NoError(t, lib.Save("breakout", d))
+	require.NoError(t, lib.Save("pp-entry", testDrill()))
+
+	entries, err := lib.List()
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "breakout", entries[0].Name)
+	assert.Equal(t, "breakout", entries[0].Meta.Title)
+	assert.Equal(t, stamp, entries[0].Meta.Created.UTC())
+
+	found, err := lib.Search("sam BREAKOUT")
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "breakout", found[0].Name)
+
+	require.NoError(t, lib.Duplicate("breakout", "breakout-2"))
+	copied, err := lib.Open("breakout-2")
+	require.NoError(t, err)
+	assert.Equal(t, "breakout-2", copied.Meta.Title)
+
+	assert.ErrorContains(t, lib.Rename("pp-entry", "breakout"), `drill "breakout" already exists`)
+	require.NoError(t, lib.Rename("pp-entry", "pp"))
+	assert.True(t, lib.Exists("pp"))
+	assert.False(t, lib.Exists("pp-entry"))
+	renamed, err := lib.Open("pp")
+	require.NoError(t, err)
+	assert.Equal(t, "pp", renamed.Meta.Title)
+
+	require.NoError(t, lib.Delete("pp"))
+	entries, err = lib.List()
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	assert.Error(t, lib.Save("../escape", testDrill()))
+
+	// An unreadable drill is still renamed, but says its title wasn't updated.
+	broken, err := lib.Path("broken")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(broken, []byte("{"), 0o644))
+	assert.ErrorContains(t, lib.Rename("broken", "mended"), "renamed broken to mended but could not update its title")
+	assert.False(t, lib.Exists("broken"))
+	assert.True(t, lib.Exists("mended"))
+}
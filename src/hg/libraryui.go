@@ -0,0 +1,199 @@
+package hg
+
+import (
+	"fmt"
+	"image"
+	"strings"
+	"time"
+
+	"github.com/ebitengine/debugui"
+)
+
+// libraryUI is the state of the drill library window.
+type libraryUI struct {
+	visible bool
+	entries []DrillEntry
+	query   string
+	// name is the target for Save As, Rename and Duplicate.
+	name string
+	// tags is the editable form of the current drill's Meta.Tags.
+	tags string
+	// confirmDelete is the drill waiting for a second Delete click.
+	confirmDelete string
+}
+
+func (g *Game) ToggleLibrary() {
+	g.libraryUI.visible = !g.libraryUI.visible
+	if g.libraryUI.visible {
+		g.refreshLibrary()
+	}
+}
+
+func (g *Game) refreshLibrary() {
+	if g.library == nil {
+		return
+	}
+	entries, err := g.library.List()
+	if err != nil {
+		g.status = fmt.Sprintf("Library: %v", err)
+	}
+	g.libraryUI.entries = entries
+	g.libraryUI.confirmDelete = ""
+}
+
+// libraryResult reports the outcome of a library operation and refreshes the listing.
+func (g *Game) libraryResult(err error, done string) {
+	if err != nil {
+		g.status = err.Error()
+	} else {
+		g.status = done
+	}
+	g.refreshLibrary()
+}
+
+func (g *Game) openFromLibrary(name string) {
+	d, err := g.library.Open(name)
+	if err != nil {
+		g.status = err.Error()
+		return
+	}
+	g.applyDrill(d)
+	g.drillName = name
+	g.drillPath, _ = g.library.Path(name)
+	g.status = "Opened " + name
+}
+
+func (g *Game) saveToLibrary(name string) {
+	d := g.drillFile()
+	if g.drillName != name {
+		if g.library.Exists(name) {
+			g.status = fmt.Sprintf("drill %q already exists", name)
+			return
+		}
+		// Saving under a new name starts a new drill.
+		d.Meta.Created = time.Time{}
+	}
+	err := g.library.Save(name, d)
+	if err == nil {
+		g.meta = d.Meta
+		g.drillName = name
+		g.drillPath, _ = g.library.Path(name)
+	}
+	g.libraryResult(err, "Saved "+name)
+}
+
+func (g *Game) renameInLibrary(name, newName string) {
+	err := g.library.Rename(name, newName)
+	if g.drillName == name && !g.library.Exists(name) && g.library.Exists(newName) {
+		g.drillName = newName
+		g.drillPath, _ = g.library.Path(newName)
+		if g.meta.Title == name {
+			g.meta.Title = newName
+		}
+	}
+	g.libraryResult(err, fmt.Sprintf("Renamed %s to %s", name, newName))
+}
+
+func (g *Game) deleteFromLibrary(name string) {
+	if g.libraryUI.confirmDelete != name {
+		g.libraryUI.confirmDelete = name
+		g.status = "Press Delete again to remove " + name
+		return
+	}
+	err := g.library.Delete(name)
+	if err == nil && g.drillName == name {
+		g.drillName = ""
+		g.drillPath = DefaultDrillPath
+	}
+	g.libraryResult(err, "Deleted "+name)
+}
+
+// splitTags parses a comma separated tag list.
+func splitTags(s string) []string {
+	var tags []string
+	for _, tag := range strings.Split(s, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func describeDrill(e DrillEntry) string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s (unreadable: %v)", e.Name, e.Err)
+	}
+	s := e.Name
+	if e.Meta.Title != "" && e.Meta.Title != e.Name {
+		s = fmt.Sprintf("%s (%s)", e.Meta.Title, e.Name)
+	}
+	if e.Meta.Author != "" {
+		s += " by " + e.Meta.Author
+	}
+	if e.Meta.AgeGroup != "" {
+		s += ", " + e.Meta.AgeGroup
+	}
+	if len(e.Meta.Tags) > 0 {
+		s += " [" + strings.Join(e.Meta.Tags, ", ") + "]"
+	}
+	if !e.Meta.Modified.IsZero() {
+		s += e.Meta.Modified.Format(" 2006-01-02")
+	}
+	return s
+}
+
+func (g *Game) libraryWindow(ctx *debugui.Context) {
+	if !g.libraryUI.visible || g.library == nil {
+		return
+	}
+	ui := &g.libraryUI
+	ctx.Window("Drill Library", image.Rect(880, 250, 1295, 795), func(layout debugui.ContainerLayout) {
+		ctx.Header("Current drill", true, func() {
+			ctx.SetGridLayout([]int{70, -1}, nil)
+			ctx.Text("Name")
+			if g.drillName != "" {
+				ctx.Text(g.drillName)
+			} else {
+				ctx.Text(g.drillPath + " (not in library)")
+			}
+			ctx.Text("Title")
+			ctx.TextField(&g.meta.Title)
+			ctx.Text("Author")
+			ctx.TextField(&g.meta.Author)
+			ctx.Text("Tags")
+			// Tags apply as they are typed, like the other fields.
+			ctx.TextField(&ui.tags)
+			g.meta.Tags = splitTags(ui.tags)
+			ctx.Text("Age group")
+			ctx.TextField(&g.meta.AgeGroup)
+		})
+
+		ctx.SetGridLayout([]int{70, -1}, nil)
+		ctx.Text("New name")
+		ctx.TextField(&ui.name)
+		ctx.SetGridLayout([]int{-1, -1}, nil)
+		ctx.Button("Save As").On(func() { g.saveToLibrary(ui.name) })
+		ctx.Button("Refresh").On(g.refreshLibrary)
+
+		ctx.SetGridLayout([]int{70, -1}, nil)
+		ctx.Text("Search")
+		ctx.TextField(&ui.query)
+		for _, e := range filterDrills(ui.entries, ui.query) {
+			ctx.IDScope(e.Name, func() {
+				ctx.SetGridLayout([]int{-1}, nil)
+				ctx.Text(describeDrill(e))
+				ctx.SetGridLayout([]int{-1, -1, -1, -1}, nil)
+				ctx.Button("Open").On(func() { g.openFromLibrary(e.Name) })
+				ctx.Button("Rename").On(func() { g.renameInLibrary(e.Name, ui.name) })
+				ctx.Button("Duplicate").On(func() {
+					g.libraryResult(g.library.Duplicate(e.Name, ui.name), "Duplicated "+e.Name)
+				})
+				label := "Delete"
+				if ui.confirmDelete == e.Name {
+					label = "Confirm"
+				}
+				ctx.Button(label).On(func() { g.deleteFromLibrary(e.Name) })
+			})
+		}
+	})
+}
@@ -1,12 +1,13 @@
 package hg
 
 import (
-	"encoding/json"
+	"bytes"
 	"fmt"
 	"image"
 	"image/color"
-	_ "image/png"
+	"math"
 	"os"
+	"path/filepath"
 	"slices"
 	"strings"
 
@@ -19,37 +20,94 @@ const (
 	ScreenH = 800
 )
 
-var rink = mustLoadImage("assets/rink.png")
+// playerRadius is the radius of every player sprite.
+const playerRadius = 20
 
 type Game struct {
 	debugui  debugui.DebugUI
 	initDone bool
 
+	// roster is the teams of the open drill, which the palette is built from.
+	roster *Roster
+	// rinkLayout is the open drill's rink, which activeRink is a view of.
+	rinkLayout       RinkLayout
 	fixedPlayers     *PlayerGroup
+	fixedProps       []*Prop
 	buttons          *ButtonGroup
 	nextPlayerId     int
 	activeDragPlayer *Player
+	activeDragProp   *Prop
 	mouseController  *MouseController
 	frames           []frame
 	activeFrameIndex int
 	currentTime      float64
-
-	dragMovesPlayer bool
+	playback         Playback
+	timeline         *Timeline
+	ghosts           ghostOptions
+	snapper          Snapper
+	collisions       collisionView
+	history          History
+	// pendingEdit is the state before the drag in progress, see beginDragEdit.
+	pendingEdit *snapshotCommand
+
+	dragMode dragMode
+	// selectedId is the Id of the player being edited in the Player window, or -1.
+	selectedId int
+	// draggingPuck is set while the puck is being placed.
+	draggingPuck bool
+	// passFrom is where the pass or shot being dragged leaves from, nil if none.
+	passFrom *SkatePoint
+	// saucer makes new passes saucer passes.
+	saucer bool
+	// passSeconds is how long new passes and shots take.
+	passSeconds float64
 
 	activeSkatePath *SkatePath
 
-	testSkatePath *SkatePathWithRadius
+	// drillPath is the file Save and Load use.
+	drillPath string
+	// drillName is the library name of the open drill, or "" if it is not in the library.
+	drillName string
+	meta      DrillMeta
+	library   *DrillLibrary
+	libraryUI libraryUI
+	// uiHasPointer is set while the mouse is over a debugui window.
+	uiHasPointer bool
+	// uiHasFocus is set while a debugui widget such as a text field has the keyboard.
+	uiHasFocus bool
+	// status is the result of the last Save or Load, shown in the debug window.
+	status string
 }
 
 type frame struct {
 	Players *PlayerGroup
 	// DurationSeconds is how long this frame plays for
 	DurationSeconds float64
+	// Puck is nil when the frame has no puck.
+	Puck *Puck
+	// Props are the cones, nets and other objects on the ice, drawn in order
+	// under the players.
+	Props []*Prop
 }
 
-type playerSaveKey struct {
-	symbol string
-	team   int
+// dragMode is what dragging a player on the rink does.
+type dragMode int
+
+const (
+	dragMove dragMode = iota
+	dragSkate
+	// dragPass drags the puck from whoever has it to a player or the ice.
+	dragPass
+)
+
+// puckPalette is the centre of the puck that is dragged onto the rink.
+var puckPalette = SkatePoint{X: 490, Y: 631}
+
+// playerSpriteKey is what a player's sprite depends on.
+type playerSpriteKey struct {
+	team  int
+	role  Role
+	label string
 }
 
 func NewGame() *Game {
@@ -57,19 +115,16 @@ func NewGame() *Game {
 		fixedPlayers:    &PlayerGroup{},
 		buttons:         &ButtonGroup{},
 		mouseController: &MouseController{},
+		drillPath:       DefaultDrillPath,
+		playback:        Playback{Speed: 1},
+		passSeconds:     0.5,
+		snapper:         Snapper{Landmarks: true, GridMetres: 1},
+		selectedId:      -1,
+		history:         History{Limit: 100},
+		timeline:        &Timeline{Rect: image.Rect(5, 591, ScreenW-5, 608)},
 		frames: []frame{{
 			Players:         &PlayerGroup{},
 			DurationSeconds: 1}},
-		testSkatePath: &SkatePathWithRadius{
-			Points: []SkatePoint{
-				{X: 200, Y: 100},
-				{X: 400, Y: 200},
-				{X: 300, Y: 300},
-			},
-			PointRadiuses:   []float32{0, 5, 0},
-			editPointIndex:  -1,
-			editRadiusIndex: -1,
-		},
 	}
 	g.activeFrameIndex = 0
 	return g
@@ -77,25 +132,43 @@ func NewGame() *Game {
 
 func (g *Game) init() {
 	g.initDone = true
-	colors := []color.RGBA{
-		{0x80, 0, 0, 0},
-		{0, 0, 0xf0, 0}}
-	symbols := strings.Split("LW,RW,C,F,F1,F2,F3,LD,RD,D,X", ",")
-	for team, col := range colors {
-		for i, symbol := range symbols {
-			s, _ := MakeCircle(symbol, 20, col)
+	g.setRoster(DefaultRoster())
+	if _, err := os.Stat(DefaultRosterPath); err == nil {
+		g.LoadRoster()
+	}
+	g.fixedProps = makeFixedProps()
+
+	g.makeButtons()
+	if lib, err := NewDrillLibrary(DefaultLibraryDir); err != nil {
+		g.status = fmt.Sprintf("Library: %v", err)
+	} else {
+		g.library = lib
+	}
+	if _, err := os.Stat(g.drillPath); err == nil {
+		g.Load()
+	}
+	g.dragMode = dragMove
+}
+
+// makeFixedPlayers builds the palette of players that are dragged onto the
+// rink, a row for each of the first two teams in roster.
+func makeFixedPlayers(roster *Roster) *PlayerGroup {
+	fixed := &PlayerGroup{}
+	for team, t := range roster.Teams[:min(len(roster.Teams), 2)] {
+		for i, rp := range t.Players[:min(len(t.Players), 11)] {
+			s, _ := MakeSprite(rp.Role, spriteLabel(rp.Symbol, rp.Number), playerRadius, roster.teamColor(team))
 			player := NewPlayerFromImage(s)
 			player.Team = team
-			player.Symbol = symbol
+			player.Symbol = rp.Symbol
+			player.Number = rp.Number
+			player.Name = rp.Name
+			player.Role = rp.Role
 			player.X = i*(40+2) + 5
 			player.Y = 610 + team*42
-			g.fixedPlayers.Add(player)
+			fixed.Add(player)
 		}
 	}
-
-	g.makeButtons()
-	g.Load()
-	g.dragMovesPlayer = true
+	return fixed
 }
 
 func (g *Game) makeButtons() {
@@ -119,16 +192,22 @@ func (g *Game) makeButtons() {
 	newCol(100)
 	button("Save", g.Save)
 	button("Load", g.Load)
+	button("Library", g.ToggleLibrary)
 
 	newCol(150)
-	button("Move", func() { g.dragMovesPlayer = true })
+	button("Move", func() { g.dragMode = dragMove })
 	button("Prev Frame", g.PreviousFrame)
 	button("New Frame", g.NewFrame)
 
 	newCol(150)
-	button("Skate", func() { g.dragMovesPlayer = false })
+	button("Skate", func() { g.dragMode = dragSkate })
 	button("Next Frame", g.NextFrame)
 	button("Delete Frame", g.DeleteFrame)
+
+	newCol(100)
+	button("Play", g.TogglePlay)
+	button("Rewind", g.Rewind)
+	button("Pass", func() { g.dragMode = dragPass })
 }
 
 type saveLoadSprite struct {
@@ -138,157 +217,448 @@ type saveLoadSprite struct {
 	Id     int
 }
 
-type saveLoadData struct {
-	NextPlayerId int
-	Frames       []frame
-}
-
-func (g *Game) Save() {
-	sld := saveLoadData{
+// drillFile captures the editor state in the on-disk format.
+func (g *Game) drillFile() *DrillFile {
+	return &DrillFile{
+		Version:      DrillFormatVersion,
+		Meta:         g.meta,
+		Roster:       *g.roster,
+		Rink:         g.rinkLayout,
 		NextPlayerId: g.nextPlayerId,
 		Frames:       g.frames,
 	}
-	data, _ := json.MarshalIndent(sld, "", " ")
-	os.WriteFile("saved.json", data, os.ModePerm)
 }
 
-func (g *Game) Load() {
-	data, err := os.ReadFile("saved.json")
+// applyDrill replaces the editor state with d.
+func (g *Game) applyDrill(d *DrillFile) {
+	g.meta = d.Meta
+	g.libraryUI.tags = strings.Join(d.Meta.Tags, ", ")
+	g.nextPlayerId = d.NextPlayerId
+	g.frames = d.Frames
+	g.activeFrameIndex = 0
+	g.currentTime = 0
+	g.selectedId = -1
+	g.history.Clear()
+	g.setRoster(d.roster())
+	g.setRinkLayout(d.Rink)
+	bindPropImages(g.frames, g.fixedProps)
+}
+
+// setRoster rebuilds the palette and player sprites for r.
+func (g *Game) setRoster(r *Roster) {
+	g.roster = r
+	g.fixedPlayers = makeFixedPlayers(r)
+	g.ghosts.OwnTeam = min(g.ghosts.OwnTeam, len(r.Teams)-1)
+	bindPlayerImages(g.frames, r, g.fixedPlayers)
+}
+
+// setRinkLayout draws the drill on l without moving anything.
+func (g *Game) setRinkLayout(l RinkLayout) {
+	g.rinkLayout = l
+	useRink(l.View())
+}
+
+// ChangeRink lays the drill out on l, moving everything to the same place
+// on the new ice.  Edits with the same name are undone together.
+func (g *Game) ChangeRink(name string, l RinkLayout) {
+	if err := l.Validate(); err != nil {
+		g.status = fmt.Sprintf("Rink failed: %v", err)
+		return
+	}
+	g.recordFieldEdit(name, func() {
+		from := activeRink
+		g.setRinkLayout(l)
+		remapFrames(g.frames, from, activeRink)
+	})
+}
+
+// bindPlayerImages gives loaded players a sprite in their team's color from
+// roster, sharing those already made for the palette.  Players of a team not
+// in the roster are grey so they can still be drawn.
+func bindPlayerImages(frames []frame, roster *Roster, fixedPlayers *PlayerGroup) {
+	extras := map[playerSpriteKey]*Player{}
+	for _, player := range fixedPlayers.Players {
+		extras[playerSpriteKey{player.Team, player.Role, player.label()}] = player
+	}
+	for _, frame := range frames {
+		for _, toLoad := range frame.Players.Players {
+			key := playerSpriteKey{toLoad.Team, toLoad.Role, toLoad.label()}
+			fixedPlayer := extras[key]
+			if fixedPlayer == nil {
+				s, _ := MakeSprite(key.role, key.label, playerRadius, roster.teamColor(toLoad.Team))
+				fixedPlayer = NewPlayerFromImage(s)
+				extras[key] = fixedPlayer
+			}
+			toLoad.CopyImagesFrom(fixedPlayer)
+		}
+	}
+}
+
+func (g *Game) Save() {
+	d := g.drillFile()
+	var err error
+	if g.drillName != "" {
+		err = g.library.Save(g.drillName, d)
+	} else {
+		err = d.SaveFile(g.drillPath)
+	}
 	if err != nil {
+		g.status = fmt.Sprintf("Save failed: %v", err)
 		return
 	}
-	sld := saveLoadData{}
-	err = json.Unmarshal(data, &sld)
+	g.meta = d.Meta
+	g.status = "Saved " + g.drillPath
+}
+
+func (g *Game) Load() {
+	d, err := LoadDrillFile(g.drillPath)
 	if err != nil {
+		g.status = fmt.Sprintf("Load failed: %v", err)
 		return
 	}
-	g.nextPlayerId = sld.NextPlayerId
+	g.applyDrill(d)
+	g.status = "Loaded " + g.drillPath
+}
 
-	extras := map[playerSaveKey]*Player{}
-	for _, player := range g.fixedPlayers.Players {
-		extras[playerSaveKey{player.Symbol, player.Team}] = player
+// LoadRoster gives the open drill the teams in DefaultRosterPath.
+func (g *Game) LoadRoster() {
+	r, err := LoadRosterFile(DefaultRosterPath)
+	if err != nil {
+		g.status = fmt.Sprintf("Roster failed: %v", err)
+		return
 	}
-	g.frames = sld.Frames
-	g.activeFrameIndex = 0
-	for _, frame := range g.frames {
-		for _, toLoad := range frame.Players.Players {
-			fixedPlayer := extras[playerSaveKey{symbol: toLoad.Symbol, team: toLoad.Team}]
-			if fixedPlayer != nil {
-				toLoad.CopyImagesFrom(fixedPlayer)
+	for _, f := range g.frames {
+		for _, p := range f.Players.Players {
+			if p.Team >= len(r.Teams) {
+				g.status = fmt.Sprintf("Roster failed: %s has %d teams but %s is on team %d", DefaultRosterPath, len(r.Teams), p.Symbol, p.Team+1)
+				return
 			}
 		}
 	}
+	g.setRoster(r)
+	g.status = "Loaded " + DefaultRosterPath
+}
+
+// ExportSVG writes the drill as it appears in the editor next to the drill
+// file, with the active frame's layer visible.
+func (g *Game) ExportSVG() {
+	path := strings.TrimSuffix(g.drillPath, filepath.Ext(g.drillPath)) + ".svg"
+	var buf bytes.Buffer
+	err := WriteDrillSVG(&buf, g.drillFile(), SVGOptions{VisibleFrame: g.activeFrameIndex})
+	if err == nil {
+		err = os.WriteFile(path, buf.Bytes(), 0o644)
+	}
+	if err != nil {
+		g.status = fmt.Sprintf("Export failed: %v", err)
+		return
+	}
+	g.status = "Exported " + path
 }
 
 func (g *Game) activeFrame() *frame {
 	return &g.frames[g.activeFrameIndex]
 }
 
+// handleTimelineDrag gives the timeline first refusal of drags and reports
+// whether it took this one.
+func (g *Game) handleTimelineDrag() bool {
+	x, y := g.mouseController.Position()
+	switch {
+	case g.mouseController.DragActive() && g.mouseController.DragStart() && !g.uiHasPointer:
+		if !g.timeline.StartDrag(g.frames, x, y) {
+			return false
+		}
+		g.playback.Playing = false
+		if g.timeline.Retiming() {
+			g.nameDragEdit("Retime frame")
+		}
+	case !g.timeline.Dragging():
+		return false
+	case g.mouseController.Dropped():
+		g.timeline.EndDrag()
+		return true
+	}
+	if t, seeking := g.timeline.Drag(g.frames, x); seeking {
+		g.seek(t)
+	}
+	return true
+}
+
+// seek moves the editor to drill time t.
+func (g *Game) seek(t float64) {
+	g.playback.Time = t
+	g.activeFrameIndex, g.currentTime = locateTime(g.frames, t)
+}
+
+// drillTime is the editor's position as seconds since the start of the drill.
+func (g *Game) drillTime() float64 {
+	return frameStartTime(g.frames, g.activeFrameIndex) + g.currentTime*g.activeFrame().DurationSeconds
+}
+
 func (g *Game) handleDragging() {
+	if g.handleTimelineDrag() || g.handlePuckDrag() {
+		return
+	}
 	if g.mouseController.DragActive() {
 		x, y := g.mouseController.Position()
-		if g.mouseController.DragStart() {
+		if g.mouseController.DragStart() && !g.uiHasPointer {
 			if player := g.activeFrame().Players.Under(x, y); player != nil {
+				g.playback.Playing = false
 				g.activeDragPlayer = player
+				g.selectedId = player.Id
 				g.activeFrame().Players.Remove(player)
 				x, y = g.mouseController.SetOffset(x-player.X, y-player.Y)
-				if g.dragMovesPlayer {
-					g.activeDragPlayer.SkatePath = nil
+				if g.dragMode == dragMove {
+					g.nameDragEdit("Move player")
+					g.activeDragPlayer.Path = nil
+					g.activeDragPlayer.Timing = SkateTiming{}
 				} else {
-					sp := &SkatePath{TargetId: g.activeDragPlayer.Id}
-					if player.SkatePath != nil {
-						// If the player already has a skate path, use it.
-						sp = player.SkatePath
-						sp.TruncatePathToFraction(float32(g.currentTime))
+					g.nameDragEdit("Skate path")
+					sp := &SkatePath{}
+					if player.Path != nil {
+						// Carry on from where the player is on their path.
+						sp.Points = slices.Clone(player.Path.Polyline())
+						sp.TruncatePathToFraction(player.pathFraction(float32(g.currentTime)))
 					}
-					//g.activeSkatePath = &SkatePath{TargetId: g.activeDragPlayer.Id}
 					g.activeSkatePath = sp
 				}
 
 			} else if fixed := g.fixedPlayers.Under(x, y); fixed != nil {
 				g.activeDragPlayer = NewPlayerFromPlayer(fixed)
 				g.activeDragPlayer.Id = g.nextPlayerId
+				g.nameDragEdit("Add player")
+				g.selectedId = g.activeDragPlayer.Id
 				g.nextPlayerId++
 				x, y = g.mouseController.SetOffset(x-fixed.X, y-fixed.Y)
+			} else if prop := propUnder(g.activeFrame().Props, x, y); prop != nil {
+				g.playback.Playing = false
+				g.activeDragProp = prop
+				g.activeFrame().Props = slices.DeleteFunc(g.activeFrame().Props, func(p *Prop) bool { return p == prop })
+				g.nameDragEdit("Move prop")
+				x, y = g.mouseController.SetOffset(x-prop.X, y-prop.Y)
+			} else if fixed := propUnder(g.fixedProps, x, y); fixed != nil {
+				g.activeDragProp = fixed.Clone()
+				g.nameDragEdit("Add prop")
+				x, y = g.mouseController.SetOffset(x-fixed.X, y-fixed.Y)
 			}
 			g.buttons.OnDragStart(x, y)
 		}
 		g.buttons.OnDrag(x, y)
 		if g.activeDragPlayer != nil {
-			g.activeDragPlayer.X = x
-			g.activeDragPlayer.Y = y
-			pt := g.activeDragPlayer.CenterPoint()
 			if g.activeSkatePath != nil {
-				g.activeSkatePath.AddPt(pt)
+				// A stroke follows the mouse; only where it ends snaps.
+				g.activeDragPlayer.X, g.activeDragPlayer.Y = x, y
+				g.activeSkatePath.AddPt(g.activeDragPlayer.CenterPoint())
+			} else {
+				sz := g.activeDragPlayer.image.Bounds().Size()
+				g.activeDragPlayer.X, g.activeDragPlayer.Y = g.snapSprite(x, y, sz.X, sz.Y)
 			}
 		}
+		if g.activeDragProp != nil {
+			look := propLooks[g.activeDragProp.Kind]
+			g.activeDragProp.X, g.activeDragProp.Y = g.snapSprite(x, y, look.w, look.h)
+		}
 	} else if g.mouseController.Dropped() {
 		x, y := g.mouseController.Position()
 		g.buttons.Dropped(x, y)
+		g.snapper.clearGuide()
 		if g.activeDragPlayer != nil {
-			if y < 590 {
+			if g.activeSkatePath != nil {
+				sz := g.activeDragPlayer.image.Bounds().Size()
+				g.activeDragPlayer.X, g.activeDragPlayer.Y = g.snapSprite(g.activeDragPlayer.X, g.activeDragPlayer.Y, sz.X, sz.Y)
+			}
+			if c := g.activeDragPlayer.CenterPoint(); activeRink.OnIce(c.X, c.Y) {
 				g.activeFrame().Players.Add(g.activeDragPlayer)
 				if g.activeSkatePath != nil {
 					g.activeSkatePath.AddClosingPt(g.activeDragPlayer.CenterPoint())
-					g.activeDragPlayer.SkatePath = g.activeSkatePath
+					g.activeDragPlayer.Path = SimplifyStroke(g.activeSkatePath.Points, SimplifyTolerance)
+					g.activeDragPlayer.Tracking = nil
+				} else if t := g.activeDragPlayer.Tracking; t != nil {
+					// Moving a tracking goalie moves them to the nearer net.
+					c := g.activeDragPlayer.CenterPoint()
+					t.Net = nearestNet(SkatePoint{X: float32(c.X), Y: float32(c.Y)})
+				}
+			} else if g.pendingEdit != nil && g.pendingEdit.name == "Add player" {
+				// Dropped back on the palette, nothing was added.
+				g.nameDragEdit("")
+			} else {
+				g.nameDragEdit("Remove player")
+				if puck := g.activeFrame().Puck; puck != nil {
+					puck.ForgetPlayer(g.activeDragPlayer)
 				}
 			}
 			g.activeDragPlayer = nil
 			g.activeSkatePath = nil
 		}
+		if g.activeDragProp != nil {
+			if c := g.activeDragProp.CenterPoint(); activeRink.OnIce(c.X, c.Y) {
+				g.activeFrame().Props = append(g.activeFrame().Props, g.activeDragProp)
+			} else if g.pendingEdit != nil && g.pendingEdit.name == "Add prop" {
+				g.nameDragEdit("")
+			} else {
+				g.nameDragEdit("Remove prop")
+			}
+			g.activeDragProp = nil
+		}
 	}
 }
 
+// snapSprite snaps the centre of a w by h sprite with its top left at (x, y)
+// and returns the sprite's new top left.
+func (g *Game) snapSprite(x, y, w, h int) (int, int) {
+	c := g.snapper.Snap(SkatePoint{X: float32(x + w/2), Y: float32(y + h/2)})
+	return int(math.Round(float64(c.X))) - w/2, int(math.Round(float64(c.Y))) - h/2
+}
+
 func (g *Game) Update() error {
 	if !g.initDone {
 		g.init()
 	}
-	g.debugui.Update(func(ctx *debugui.Context) error {
-		ctx.Window("Test", image.Rect(526, 609, 875, 790), func(layout debugui.ContainerLayout) {
+	capturing, _ := g.debugui.Update(func(ctx *debugui.Context) error {
+		ctx.Window("Test", image.Rect(526, 652, 875, 795), func(layout debugui.ContainerLayout) {
 			ctx.Text(fmt.Sprintf("Frame: %d (%d)", g.activeFrameIndex+1, len(g.frames)))
 			ctx.NumberFieldF(&g.activeFrame().DurationSeconds, 0.01, 1)
 			if g.activeFrame().DurationSeconds < 0 {
 				g.activeFrame().DurationSeconds = 0
 			}
-			ctx.NumberFieldF(&g.currentTime, 0.01, 1)
+			ctx.Text(fmt.Sprintf("Time: %.2fs of %.2fs", g.drillTime(), drillDuration(g.frames)))
+			ctx.SetGridLayout([]int{-1, -1}, nil)
+			ctx.Checkbox(&g.playback.Loop, "Loop")
+			ctx.NumberFieldF(&g.playback.Speed, 0.1, 1)
+			if g.playback.Speed < 0 {
+				g.playback.Speed = 0
+			}
+			ctx.Checkbox(&g.ghosts.Show, "Ghosts")
+			ctx.Checkbox(&g.ghosts.NextFrame, "Next frame")
+			ctx.Text("Own team")
+			ctx.Slider(&g.ghosts.OwnTeam, 0, len(g.roster.Teams)-1, 1)
+			ctx.Checkbox(&g.collisions.Show, fmt.Sprintf("Collisions (%d)", g.collisions.Count()))
+			ctx.Button("Nudge path").On(g.NudgeCollision)
+			ctx.Checkbox(&g.saucer, "Saucer")
+			ctx.NumberFieldF(&g.passSeconds, 0.05, 2)
+			if g.passSeconds < 0 {
+				g.passSeconds = 0
+			}
+			ctx.SetGridLayout(nil, nil)
+			ctx.SetGridLayout([]int{-1, -1, -1, -1}, nil)
+			ctx.Button("Undo").On(g.Undo)
+			ctx.Button("Redo").On(g.Redo)
+			ctx.Button("Export SVG").On(g.ExportSVG)
+			ctx.Button("Roster").On(g.LoadRoster)
+			ctx.SetGridLayout(nil, nil)
+			g.rinkControls(ctx)
+			if g.status != "" {
+				ctx.Text(g.status)
+			}
 		})
+		g.libraryWindow(ctx)
+		g.playerWindow(ctx)
 		return nil
 	})
+	g.uiHasPointer = capturing&debugui.InputCapturingStateHover != 0
+	g.uiHasFocus = capturing&debugui.InputCapturingStateFocus != 0
 	g.mouseController.Update()
+	if g.mouseController.DragStart() && !g.uiHasPointer {
+		g.beginDragEdit()
+	}
+	if !g.uiHasFocus {
+		g.handleUndoKeys()
+	}
+	if g.mouseController.Clicked() && !g.uiHasPointer {
+		g.selectAt(g.mouseController.Position())
+	}
 	if g.mouseController.IsDoubleClick() {
 		fmt.Println("Double click")
 	}
-	g.handleDragging()
-	g.activeFrame().Players.Interpolate(float32(g.currentTime))
-
-	g.testSkatePath.UpdateForEdit(g.mouseController)
+	if g.playback.Playing {
+		g.playback.Advance(g.frames, 1/float64(ebiten.TPS()))
+		g.activeFrameIndex, g.currentTime = locateTime(g.frames, g.playback.Time)
+	}
+	if !g.editSelectedPath() {
+		g.handleDragging()
+	}
+	g.activeFrame().Interpolate(float32(g.currentTime))
+	g.collisions.Update(g.activeFrame().Players)
+	if g.mouseController.Dropped() {
+		g.endDragEdit()
+	}
 	return nil
 }
 
-func (g *Game) NewFrame() {
-	frame := *g.activeFrame()
-	frame.Players = frame.Players.CloneForNewFrame()
-	g.frames = append(g.frames, frame)
+// TogglePlay plays the drill from the current frame and time, or pauses it.
+// Playing from the end starts again from the beginning.
+func (g *Game) TogglePlay() {
+	if g.playback.Playing {
+		g.playback.Playing = false
+		return
+	}
+	g.playback.Seek(g.frames, g.activeFrameIndex, g.currentTime)
+	if g.playback.AtEnd(g.frames) {
+		g.playback.Time = 0
+	}
+	g.playback.Playing = true
+}
+
+// Rewind stops playback and returns to the start of the first frame.
+func (g *Game) Rewind() {
+	g.playback.Playing = false
+	g.playback.Time = 0
+	g.activeFrameIndex = 0
 	g.currentTime = 0
-	g.NextFrame()
+}
+
+// NudgeCollision bends a skate path to clear the first collision in the frame.
+func (g *Game) NudgeCollision() {
+	c, ok := g.collisions.First()
+	if !ok {
+		g.status = "No collisions"
+		return
+	}
+	player, path := ProposeNudge(g.activeFrame().Players, c)
+	if player == nil {
+		g.status = "Neither player is skating, move one of them"
+		return
+	}
+	g.playback.Playing = false
+	g.recordEdit("Nudge path", func() { player.Path = path })
+}
+
+func (g *Game) NewFrame() {
+	g.playback.Playing = false
+	g.recordEdit("New frame", func() {
+		frame := *g.activeFrame()
+		if frame.Puck != nil {
+			frame.Puck = frame.Puck.nextFramePuck(frame.Players)
+		}
+		frame.Players = frame.Players.CloneForNewFrame()
+		frame.Props = cloneProps(frame.Props)
+		g.frames = append(g.frames, frame)
+		g.currentTime = 0
+		g.NextFrame()
+	})
 }
 
 func (g *Game) DeleteFrame() {
+	g.playback.Playing = false
 	if len(g.frames) > 1 {
-		g.frames = g.frames[:len(g.frames)-1]
-		if g.activeFrameIndex >= len(g.frames) {
-			g.activeFrameIndex = len(g.frames) - 1
-		}
+		g.recordEdit("Delete frame", func() {
+			g.frames = g.frames[:len(g.frames)-1]
+			if g.activeFrameIndex >= len(g.frames) {
+				g.activeFrameIndex = len(g.frames) - 1
+			}
+		})
 	}
 }
 
 func (g *Game) PreviousFrame() {
+	g.playback.Playing = false
 	if g.activeFrameIndex > 0 {
 		g.activeFrameIndex--
 	}
 }
 
 func (g *Game) NextFrame() {
+	g.playback.Playing = false
 	if g.activeFrameIndex < len(g.frames)-1 {
 		g.activeFrameIndex++
 	}
@@ -297,14 +667,28 @@ func (g *Game) NextFrame() {
 func (g *Game) Draw(screen *ebiten.Image) {
 	screen.Fill(color.White)
 
-	screen.DrawImage(rink, &ebiten.DrawImageOptions{})
-	g.fixedPlayers.Draw(screen)
+	activeRink.Draw(screen)
+	g.snapper.drawGrid(screen)
+	g.timeline.Draw(screen, g.frames, g.activeFrameIndex, g.drillTime())
+	for _, p := range g.fixedPlayers.Players {
+		p.drawSprite(screen, 1)
+	}
+	drawProps(screen, g.fixedProps)
 	g.buttons.Draw(screen)
 
+	drawProps(screen, g.activeFrame().Props)
+	g.ghosts.Draw(screen, g.frames, g.activeFrameIndex)
 	g.activeFrame().Players.Draw(screen)
 	if g.activeDragPlayer != nil {
 		g.activeDragPlayer.DrawWithAlpha(screen, 0.8)
 	}
+	if g.activeDragProp != nil {
+		g.activeDragProp.Draw(screen, 0.8)
+	}
+	g.drawRushed(screen)
+	g.collisions.Draw(screen, g.activeFrame().Players, float32(g.currentTime), g.timeline, g.frames, g.activeFrameIndex)
+	g.drawSelection(screen)
+	g.drawPuck(screen)
 
 	if g.activeSkatePath != nil {
 		if g.activeDragPlayer != nil {
@@ -315,8 +699,15 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	}
 
 	g.DrawTest(screen)
+	if g.mouseController.DragActive() {
+		g.snapper.drawGuide(screen)
+	}
 
-	g.testSkatePath.DrawForEdit(screen)
+	if p := g.selectedPlayer(); p != nil && g.activeDragPlayer == nil {
+		if path, ok := p.Path.(EditablePath); ok {
+			path.DrawForEdit(screen)
+		}
+	}
 
 	g.debugui.Draw(screen)
 }
@@ -339,18 +730,3 @@ func (g *Game) DrawTest(screen *ebiten.Image) {
 func (g *Game) Layout(outsideWidth, outsideHeight int) (screenWidth, screenHeight int) {
 	return ScreenW, ScreenH
 }
-
-func mustLoadImage(name string) *ebiten.Image {
-	f, err := os.Open(name)
-	if err != nil {
-		return nil
-	}
-	defer f.Close()
-
-	img, _, err := image.Decode(f)
-	if err != nil {
-		panic(err)
-	}
-
-	return ebiten.NewImageFromImage(img)
-}
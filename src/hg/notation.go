@@ -0,0 +1,294 @@
+package hg
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// Notation is a line from the vocabulary of printed hockey diagrams.
+type Notation string
+
+const (
+	// NotationSkate is a solid line.
+	NotationSkate Notation = ""
+	// NotationStickhandle is a wavy line.
+	NotationStickhandle Notation = "stickhandle"
+	// NotationBackwards is a zigzag, used for segments skated backwards.
+	NotationBackwards Notation = "backwards"
+	// NotationPass is a dashed line.
+	NotationPass Notation = "pass"
+	// NotationShot is a double line.
+	NotationShot Notation = "shot"
+)
+
+// PathNotations lists the lines a player's path may be drawn with, in the
+// order the editor offers them.
+var PathNotations = []Notation{NotationSkate, NotationStickhandle}
+
+func (n Notation) String() string {
+	if n == NotationSkate {
+		return "skate"
+	}
+	return string(n)
+}
+
+// PathEnd is what is drawn where a line finishes.
+type PathEnd string
+
+const (
+	// EndArrow is an open arrowhead.
+	EndArrow PathEnd = ""
+	// EndStop is a bar across the end, for a hard stop.
+	EndStop PathEnd = "stop"
+	// EndNone leaves the line bare.
+	EndNone PathEnd = "none"
+)
+
+// PathEnds lists every end in the order the editor offers them.
+var PathEnds = []PathEnd{EndArrow, EndStop, EndNone}
+
+func (e PathEnd) String() string {
+	if e == EndArrow {
+		return "arrow"
+	}
+	return string(e)
+}
+
+// PathStyle is how a player's path is drawn.
+type PathStyle struct {
+	// Line is the notation for the path, except for segments skated
+	// backwards, which are always NotationBackwards.
+	Line Notation
+	End  PathEnd
+}
+
+// Sizes of the notation in pixels.
+const (
+	pathLineWidth  = 3
+	puckLineWidth  = 2
+	waveLength     = 14
+	waveAmplitude  = 4
+	dashLength     = 6
+	shotSeparation = 3
+	arrowheadSize  = 10
+	stopBarLength  = 16
+)
+
+// stroke is one polyline of a drawn notation.  The editor and SVG export
+// both draw notation from strokes, so they match.
+type stroke struct {
+	points []SkatePoint
+	width  float32
+}
+
+// notationStrokes draws points as notation n with lines width wide.
+func notationStrokes(points []SkatePoint, n Notation, width float32) []stroke {
+	if len(points) < 2 {
+		return nil
+	}
+	switch n {
+	case NotationStickhandle:
+		return []stroke{{wave(points, 2, func(d float32) float32 {
+			return float32(math.Sin(2 * math.Pi * float64(d) / waveLength))
+		}), width}}
+	case NotationBackwards:
+		return []stroke{{wave(points, waveLength/4, func(d float32) float32 {
+			// Out, back through the line, then out the other side.
+			return [4]float32{0, 1, 0, -1}[int(math.Round(float64(d*4/waveLength)))%4]
+		}), width}}
+	case NotationPass:
+		var dashes []stroke
+		total := polylineLength(points)
+		for from := float32(0); from < total; from += 2 * dashLength {
+			dashes = append(dashes, stroke{slicePolyline(points, from/total, min(from+dashLength, total)/total), width})
+		}
+		return dashes
+	case NotationShot:
+		return []stroke{
+			{offsetPolyline(points, shotSeparation), width},
+			{offsetPolyline(points, -shotSeparation), width},
+		}
+	}
+	return []stroke{{points, width}}
+}
+
+// endStrokes draws end at the end of points.
+func endStrokes(points []SkatePoint, end PathEnd, width float32) []stroke {
+	if len(points) < 2 {
+		return nil
+	}
+	tip := points[len(points)-1]
+	// Aim along the last few pixels, which a short last step may not.
+	total := polylineLength(points)
+	if total == 0 {
+		return nil
+	}
+	from := slicePolyline(points, max(0, total-arrowheadSize)/total, 1)[0]
+	switch end {
+	case EndArrow:
+		arms := arrowheadArms(from, tip, arrowheadSize)
+		return []stroke{{[]SkatePoint{arms[0], tip, arms[1]}, width}}
+	case EndStop:
+		across := SkatePoint{X: from.Y - tip.Y, Y: tip.X - from.X}.Normalize().Mul(stopBarLength / 2)
+		return []stroke{{[]SkatePoint{tip.Add(across), tip.Sub(across)}, width}}
+	}
+	return nil
+}
+
+// pathStrokes draws a path through points with the given segments, see
+// Path.Segments, in style.
+func pathStrokes(points []SkatePoint, segments []float32, backwards func(segment int) bool, style PathStyle) []stroke {
+	var strokes []stroke
+	notation := func(i int) Notation {
+		if backwards(i) {
+			return NotationBackwards
+		}
+		return style.Line
+	}
+	// Draw runs of segments with the same notation as one line so the waves
+	// don't restart at every control point.
+	for first := 0; first+1 < len(segments); {
+		last := first + 1
+		for last+1 < len(segments) && notation(last) == notation(first) {
+			last++
+		}
+		run := slicePolyline(points, segments[first], segments[last])
+		strokes = append(strokes, notationStrokes(run, notation(first), pathLineWidth)...)
+		first = last
+	}
+	return append(strokes, endStrokes(points, style.End, pathLineWidth)...)
+}
+
+// arrowheadArms are the ends of the two strokes of an open arrowhead at tip
+// pointing away from from.
+func arrowheadArms(from, tip SkatePoint, size float32) [2]SkatePoint {
+	heading := float64(tip.Sub(from).Heading())
+	var arms [2]SkatePoint
+	for i, a := range []float64{heading + math.Pi*0.85, heading - math.Pi*0.85} {
+		arms[i] = SkatePoint{X: tip.X + size*float32(math.Cos(a)), Y: tip.Y + size*float32(math.Sin(a))}
+	}
+	return arms
+}
+
+// polylineLength is the length of the line through points.
+func polylineLength(points []SkatePoint) float32 {
+	return (&SkatePath{Points: points}).TotalLength()
+}
+
+// slicePolyline returns the part of the line through points between the
+// from and to fractions of its length.
+func slicePolyline(points []SkatePoint, from, to float32) []SkatePoint {
+	total := polylineLength(points)
+	start, end := from*total, to*total
+	result := []SkatePoint{(&SkatePath{Points: points}).Interpolate(from)}
+	var covered float32
+	for i := 1; i < len(points); i++ {
+		covered += points[i].Sub(points[i-1]).Length()
+		if covered >= end {
+			break
+		}
+		if covered > start {
+			result = append(result, points[i])
+		}
+	}
+	return append(result, (&SkatePath{Points: points}).Interpolate(to))
+}
+
+// offsetPolyline moves each of points distance to the left of the line.
+func offsetPolyline(points []SkatePoint, distance float32) []SkatePoint {
+	moved := make([]SkatePoint, len(points))
+	for i, p := range points {
+		along := points[min(i+1, len(points)-1)].Sub(points[max(i-1, 0)]).Normalize()
+		moved[i] = p.Add(SkatePoint{X: along.Y, Y: -along.X}.Mul(distance))
+	}
+	return moved
+}
+
+// wave resamples points every step pixels, moving each sideways by
+// waveAmplitude times shape of the distance along.  The wave fades in and
+// out over the ends so runs of notation join up.
+func wave(points []SkatePoint, step float32, shape func(d float32) float32) []SkatePoint {
+	even := resample(points, step)
+	total := polylineLength(points)
+	result := make([]SkatePoint, len(even))
+	for i, p := range even {
+		d := min(float32(i)*step, total)
+		ahead := even[min(i+1, len(even)-1)].Sub(even[max(i-1, 0)]).Normalize()
+		fade := min(1, d/(waveLength/2), (total-d)/(waveLength/2))
+		result[i] = p.Add(SkatePoint{X: ahead.Y, Y: -ahead.X}.Mul(waveAmplitude * fade * shape(d)))
+	}
+	return result
+}
+
+// resample returns points every step pixels along the line through points,
+// and its last point.
+func resample(points []SkatePoint, step float32) []SkatePoint {
+	result := []SkatePoint{points[0]}
+	next := step
+	var covered float32
+	for i := 1; i < len(points); i++ {
+		seg := points[i].Sub(points[i-1])
+		length := seg.Length()
+		for length > 0 && covered+length >= next {
+			result = append(result, points[i-1].Add(seg.Mul((next-covered)/length)))
+			next += step
+		}
+		covered += length
+	}
+	if last := points[len(points)-1]; result[len(result)-1] != last {
+		result = append(result, last)
+	}
+	return result
+}
+
+// pathColor is the colour skate paths are drawn in.
+var pathColor = color.NRGBA{0, 0, 0, 0x99}
+
+// drawStrokes draws strokes on screen in col.
+func drawStrokes(screen *ebiten.Image, strokes []stroke, col color.Color) {
+	c := color.NRGBAModel.Convert(col).(color.NRGBA)
+	for _, s := range strokes {
+		path := vector.Path{}
+		path.MoveTo(s.points[0].X, s.points[0].Y)
+		for _, p := range s.points[1:] {
+			path.LineTo(p.X, p.Y)
+		}
+		vertices, indices := path.AppendVerticesAndIndicesForStroke(nil, nil, &vector.StrokeOptions{
+			Width:    s.width,
+			LineJoin: vector.LineJoinRound,
+			LineCap:  vector.LineCapRound,
+		})
+		for i := range vertices {
+			vertices[i].SrcX = 1
+			vertices[i].SrcY = 1
+			vertices[i].ColorR = float32(c.R) / 0xff
+			vertices[i].ColorG = float32(c.G) / 0xff
+			vertices[i].ColorB = float32(c.B) / 0xff
+			vertices[i].ColorA = float32(c.A) / 0xff
+		}
+		screen.DrawTriangles(vertices, indices, whiteSubImage, &ebiten.DrawTrianglesOptions{
+			AntiAlias:      true,
+			FillRule:       ebiten.FillRuleNonZero,
+			ColorScaleMode: ebiten.ColorScaleModeStraightAlpha,
+		})
+	}
+}
+
+// writeSVGStrokes writes strokes as SVG polylines in col.
+func writeSVGStrokes(w io.Writer, strokes []stroke, col color.Color) {
+	if len(strokes) == 0 {
+		return
+	}
+	c := color.NRGBAModel.Convert(col).(color.NRGBA)
+	fmt.Fprintf(w, `<g fill="none" stroke="#%02x%02x%02x" stroke-opacity="%.2f" stroke-linejoin="round" stroke-linecap="round">`,
+		c.R, c.G, c.B, float32(c.A)/0xff)
+	for _, s := range strokes {
+		fmt.Fprintf(w, `<polyline stroke-width="%g" points="%s"/>`, s.width, svgPoints(s.points))
+	}
+	fmt.Fprintln(w, `</g>`)
+}
@@ -0,0 +1,59 @@
+package hg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotationStrokes(t *testing.T) {
+	line := []SkatePoint{{X: 0, Y: 0}, {X: 120, Y: 0}}
+
+	solid := notationStrokes(line, NotationSkate, pathLineWidth)
+	require.Len(t, solid, 1)
+	assert.Equal(t, line, solid[0].points)
+
+	// Waves and zigzags stay within their amplitude and start and end on the line.
+	for _, n := range []Notation{NotationStickhandle, NotationBackwards} {
+		wavy := notationStrokes(line, n, pathLineWidth)
+		require.Len(t, wavy, 1)
+		pts := wavy[0].points
+		assert.Greater(t, len(pts), 8)
+		assert.Equal(t, line[0], pts[0])
+		assert.InDelta(t, 0, pts[len(pts)-1].Y, 0.01)
+		var furthest float32
+		for _, p := range pts {
+			furthest = max(furthest, p.Y, -p.Y)
+		}
+		assert.InDelta(t, waveAmplitude, furthest, 0.5, n.String())
+	}
+
+	dashes := notationStrokes(line, NotationPass, puckLineWidth)
+	assert.Len(t, dashes, 10)
+	assert.InDelta(t, dashLength, polylineLength(dashes[0].points), 0.01)
+
+	shot := notationStrokes(line, NotationShot, puckLineWidth)
+	require.Len(t, shot, 2)
+	assert.Equal(t, float32(-shotSeparation), shot[0].points[0].Y)
+	assert.Equal(t, float32(shotSeparation), shot[1].points[0].Y)
+
+	stop := endStrokes(line, EndStop, pathLineWidth)
+	require.Len(t, stop, 1)
+	assert.Equal(t, []SkatePoint{{X: 120, Y: stopBarLength / 2}, {X: 120, Y: -stopBarLength / 2}}, stop[0].points)
+	assert.Len(t, endStrokes(line, EndArrow, pathLineWidth)[0].points, 3)
+	assert.Empty(t, endStrokes(line, EndNone, pathLineWidth))
+}
+
+func TestPathStrokes(t *testing.T) {
+	points := []SkatePoint{{X: 0, Y: 0}, {X: 100, Y: 0}, {X: 200, Y: 0}, {X: 300, Y: 0}}
+	segments := []float32{0, 1.0 / 3, 2.0 / 3, 1}
+	backwards := func(i int) bool { return i == 2 }
+
+	// The forward segments are drawn as one line, then the backwards one, then the arrowhead.
+	strokes := pathStrokes(points, segments, backwards, PathStyle{})
+	require.Len(t, strokes, 3)
+	assert.Equal(t, []SkatePoint{{X: 0, Y: 0}, {X: 100, Y: 0}, {X: 200, Y: 0}}, strokes[0].points)
+	assert.Equal(t, SkatePoint{X: 200, Y: 0}, strokes[1].points[0])
+	assert.Greater(t, len(strokes[1].points), 2)
+}
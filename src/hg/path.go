@@ -0,0 +1,142 @@
+package hg
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Path is the shape a player skates during a frame.  Players, collisions and
+// export only use this interface, so a new kind of path needs a type that
+// implements it and an entry in pathKinds.
+type Path interface {
+	// Kind is the Type the path is saved with, see pathKinds.
+	Kind() string
+	// Polyline returns the path as closely spaced points.
+	Polyline() []SkatePoint
+	TotalLength() float32
+	// Interpolate returns the point fraction of the way along the path by length.
+	Interpolate(fraction float32) SkatePoint
+	// Heading is the direction of travel in radians at fraction.
+	Heading(fraction float32) float32
+	// Segments returns where each control point falls as a fraction of the
+	// length, from 0 to 1, so segment i runs from Segments()[i] to
+	// Segments()[i+1].
+	Segments() []float32
+	// BackwardsOn and SetBackwards are which way each segment is skated,
+	// see Facing.
+	BackwardsOn(segment int) bool
+	SetBackwards(segment int, backwards bool)
+	// Bounds is the smallest rectangle holding the path.
+	Bounds() image.Rectangle
+	Draw(screen *ebiten.Image)
+	// ClonePath returns a deep copy of the path that is not being edited.
+	ClonePath() Path
+	// Transform scales the path by scale about the origin and then moves it
+	// by offset, as when the drill changes rink layout.
+	Transform(scale float32, offset SkatePoint)
+	// Validate reports a path that could not have been drawn in the editor.
+	Validate() error
+}
+
+// EditablePath is a Path the editor can reshape by dragging its handles.
+type EditablePath interface {
+	Path
+	// UpdateForEdit drags handles with the mouse, snapping points placed on
+	// the rink with snapper, which may be nil.
+	UpdateForEdit(mouseController *MouseController, snapper *Snapper)
+	// Editing reports whether a handle is being dragged.
+	Editing() bool
+	DrawForEdit(screen *ebiten.Image)
+}
+
+// pathKinds makes an empty path of each Type a drill file may hold.
+var pathKinds = map[string]func() Path{
+	"freehand": func() Path { return &SkatePath{} },
+	"radius":   func() Path { return &SkatePathWithRadius{} },
+	"spline":   func() Path { return &SplinePath{} },
+}
+
+// marshalPath encodes path as a JSON object with its Kind as "Type".
+func marshalPath(path Path) (json.RawMessage, error) {
+	if path == nil {
+		return json.RawMessage("null"), nil
+	}
+	data, err := json.Marshal(path)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	fields["Type"], _ = json.Marshal(path.Kind())
+	return json.Marshal(fields)
+}
+
+// unmarshalPath decodes a path written by marshalPath.
+func unmarshalPath(data json.RawMessage) (Path, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	var kind string
+	if err := json.Unmarshal(fields["Type"], &kind); err != nil {
+		return nil, fmt.Errorf("path Type: %w", err)
+	}
+	newPath, ok := pathKinds[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown path Type %q", kind)
+	}
+	delete(fields, "Type")
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	path := newPath()
+	if err := decodeStrict(data, path); err != nil {
+		return nil, fmt.Errorf("%s path: %w", kind, err)
+	}
+	return path, nil
+}
+
+// headingAlong is the direction of travel at fraction along points.
+func headingAlong(points []SkatePoint, fraction float32) float32 {
+	line := &SkatePath{Points: points}
+	total := line.TotalLength()
+	if total == 0 {
+		return 0
+	}
+	// Look a couple of pixels either side so corners have a direction.
+	step := 2 / total
+	a := line.Interpolate(max(0, fraction-step))
+	b := line.Interpolate(min(1, fraction+step))
+	return b.Sub(a).Heading()
+}
+
+// transformPoints scales points by scale about the origin and moves them by offset.
+func transformPoints(points []SkatePoint, scale float32, offset SkatePoint) {
+	for i, p := range points {
+		points[i] = p.Mul(scale).Add(offset)
+	}
+}
+
+// polylineBounds is the smallest rectangle holding points.
+func polylineBounds(points []SkatePoint) image.Rectangle {
+	if len(points) == 0 {
+		return image.Rectangle{}
+	}
+	minX, minY := float64(points[0].X), float64(points[0].Y)
+	maxX, maxY := minX, minY
+	for _, p := range points[1:] {
+		minX, maxX = math.Min(minX, float64(p.X)), math.Max(maxX, float64(p.X))
+		minY, maxY = math.Min(minY, float64(p.Y)), math.Max(maxY, float64(p.Y))
+	}
+	return image.Rect(int(math.Floor(minX)), int(math.Floor(minY)), int(math.Ceil(maxX)), int(math.Ceil(maxY)))
+}
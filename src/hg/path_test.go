@@ -0,0 +1,44 @@
+package hg
+
+import (
+	"encoding/json"
+	"image"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPathKinds(t *testing.T) {
+	corner := []SkatePoint{{X: 0, Y: 0}, {X: 100, Y: 0}, {X: 100, Y: 100}}
+	paths := []Path{
+		&SkatePath{Points: corner},
+		(&SkatePathWithRadius{Points: corner, PointRadiuses: []float32{0, 20, 0}}).Clone(),
+		NewSplineThrough(corner),
+	}
+	for _, path := range paths {
+		t.Run(path.Kind(), func(t *testing.T) {
+			assert.NoError(t, path.Validate())
+			assert.Equal(t, corner[0], path.Interpolate(0))
+			// Setting off along x and finishing along y.
+			assert.InDelta(t, 1, math.Cos(float64(path.Heading(0))), 0.01)
+			assert.InDelta(t, 1, math.Sin(float64(path.Heading(1))), 0.01)
+			assert.Equal(t, image.Rect(0, 0, 100, 100), path.Bounds().Intersect(image.Rect(0, 0, 100, 100)))
+
+			data, err := json.Marshal(&Player{Id: 1, Path: path})
+			require.NoError(t, err)
+			assert.Contains(t, string(data), `"Type":"`+path.Kind()+`"`)
+			p := &Player{}
+			require.NoError(t, json.Unmarshal(data, p))
+			assert.Equal(t, path, p.Path)
+			assert.Equal(t, path, path.ClonePath())
+		})
+	}
+
+	p := &Player{}
+	require.NoError(t, json.Unmarshal([]byte(`{"Path": null}`), p))
+	assert.Nil(t, p.Path)
+	assert.ErrorContains(t, json.Unmarshal([]byte(`{"Path": {"Type": "zigzag"}}`), p), `unknown path Type "zigzag"`)
+	assert.ErrorContains(t, json.Unmarshal([]byte(`{"Path": {"Type": "freehand", "TargetId": 1}}`), p), `unknown field "TargetId"`)
+}
@@ -0,0 +1,78 @@
+package hg
+
+import "math"
+
+// Playback plays a whole drill on a single clock, moving through the frames
+// using each frame's DurationSeconds.
+type Playback struct {
+	Playing bool
+	Loop    bool
+	// Speed multiplies real time, 1 plays the drill at its true pace.
+	Speed float64
+	// Time is seconds since the start of the drill.
+	Time float64
+}
+
+// drillDuration is the total length of all frames in seconds.
+func drillDuration(frames []frame) float64 {
+	total := 0.0
+	for _, f := range frames {
+		total += f.DurationSeconds
+	}
+	return total
+}
+
+// frameStartTime is the drill time at which frame index starts.
+func frameStartTime(frames []frame, index int) float64 {
+	start := 0.0
+	for _, f := range frames[:index] {
+		start += f.DurationSeconds
+	}
+	return start
+}
+
+// locateTime returns the frame playing at drill time t and how far through
+// it playback is, from 0 to 1.  Zero length frames are passed through.
+func locateTime(frames []frame, t float64) (index int, fraction float64) {
+	start := 0.0
+	for i, f := range frames {
+		end := start + f.DurationSeconds
+		if t < end || i == len(frames)-1 {
+			if f.DurationSeconds <= 0 {
+				return i, 1
+			}
+			return i, math.Min(1, math.Max(0, (t-start)/f.DurationSeconds))
+		}
+		start = end
+	}
+	return 0, 0
+}
+
+// Seek moves the clock to frame index, fraction of the way through.
+func (p *Playback) Seek(frames []frame, index int, fraction float64) {
+	p.Time = frameStartTime(frames, index) + fraction*frames[index].DurationSeconds
+}
+
+// Advance moves the clock on by dt real seconds if playing.  At the end of
+// the drill it wraps when looping and otherwise stops.
+func (p *Playback) Advance(frames []frame, dt float64) {
+	if !p.Playing {
+		return
+	}
+	p.Time += dt * p.Speed
+	total := drillDuration(frames)
+	if p.Time < total {
+		return
+	}
+	if p.Loop && total > 0 {
+		p.Time = math.Mod(p.Time, total)
+		return
+	}
+	p.Time = total
+	p.Playing = false
+}
+
+// AtEnd reports whether the clock has reached the end of the drill.
+func (p *Playback) AtEnd(frames []frame) bool {
+	return p.Time >= drillDuration(frames)
+}
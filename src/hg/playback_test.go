@@ -0,0 +1,58 @@
+package hg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func framesOf(durations ...float64) []frame {
+	var frames []frame
+	for _, d := range durations {
+		frames = append(frames, frame{Players: &PlayerGroup{}, DurationSeconds: d})
+	}
+	return frames
+}
+
+func TestLocateTime(t *testing.T) {
+	frames := framesOf(2, 0, 1)
+	i, f := locateTime(frames, 0)
+	assert.Equal(t, 0, i)
+	assert.Equal(t, 0.0, f)
+
+	i, f = locateTime(frames, 1)
+	assert.Equal(t, 0, i)
+	assert.Equal(t, 0.5, f)
+
+	// The zero length frame is skipped over.
+	i, f = locateTime(frames, 2)
+	assert.Equal(t, 2, i)
+	assert.Equal(t, 0.0, f)
+
+	i, f = locateTime(frames, 10)
+	assert.Equal(t, 2, i)
+	assert.Equal(t, 1.0, f)
+
+	assert.Equal(t, 2.0, frameStartTime(frames, 2))
+	assert.Equal(t, 3.0, drillDuration(frames))
+}
+
+func TestPlaybackAdvance(t *testing.T) {
+	frames := framesOf(1, 1)
+	p := Playback{Playing: true, Speed: 2}
+	p.Advance(frames, 0.25)
+	assert.Equal(t, 0.5, p.Time)
+
+	p.Advance(frames, 1)
+	assert.Equal(t, 2.0, p.Time)
+	assert.False(t, p.Playing)
+	assert.True(t, p.AtEnd(frames))
+
+	p = Playback{Playing: true, Loop: true, Speed: 1, Time: 1.5}
+	p.Advance(frames, 1)
+	assert.InDelta(t, 0.5, p.Time, 1e-9)
+	assert.True(t, p.Playing)
+
+	p.Seek(frames, 1, 0.25)
+	assert.Equal(t, 1.25, p.Time)
+}
@@ -1,6 +1,7 @@
 package hg
 
 import (
+	"encoding/json"
 	"image"
 	"image/color"
 
@@ -12,12 +13,79 @@ import (
 type Player struct {
 	image      *ebiten.Image
 	alphaImage *image.Alpha
-	X          int
-	Y          int
-	Id         int
-	Team       int
-	Symbol     string
-	SkatePath  *SkatePath
+	// X and Y are the top left of the player's sprite.
+	X      int
+	Y      int
+	Id     int
+	Team   int
+	Symbol string
+	// Number and Name are from the roster, see RosterPlayer.
+	Number int
+	Name   string
+	// Role sets the player's sprite shape.
+	Role Role
+	// Path is what the player skates in this frame, nil if they stay put.
+	Path Path
+	// Timing is when during the frame they skate Path.
+	Timing SkateTiming
+	// Style is how Path is drawn.
+	Style PathStyle
+	// Skating is how hard this player skates, nil for DefaultSkaterLimits.
+	Skating *SkaterLimits
+	// Heading is the way the player faces in radians, clockwise from along
+	// +X.  On a Path it follows the path, see HeadingAt.
+	Heading float32
+	// Shoots is ShootsLeft or ShootsRight, the side they carry their stick.
+	// Left if unset.
+	Shoots string
+	// Tracking, for goalies with no Path, keeps them in their crease facing
+	// the puck.  Nil if they stay put.
+	Tracking *GoalieTracking
+
+	// track is cached by skateTrack.
+	track *skateTrack
+}
+
+// skateTrack is a player's Path flattened and planned at their limits.
+type skateTrack struct {
+	path       Path
+	limits     SkaterLimits
+	line       *SkatePath
+	segments   []float32
+	trajectory *Trajectory
+}
+
+// playerFields is Player without its JSON methods.
+type playerFields Player
+
+// MarshalJSON writes Path with its Type, see marshalPath.
+func (p *Player) MarshalJSON() ([]byte, error) {
+	path, err := marshalPath(p.Path)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(struct {
+		*playerFields
+		Path json.RawMessage
+	}{(*playerFields)(p), path})
+}
+
+// UnmarshalJSON reads Path by its Type, see unmarshalPath.
+func (p *Player) UnmarshalJSON(data []byte) error {
+	aux := struct {
+		*playerFields
+		Path json.RawMessage
+	}{playerFields: (*playerFields)(p)}
+	if err := decodeStrict(data, &aux); err != nil {
+		return err
+	}
+	path, err := unmarshalPath(aux.Path)
+	if err != nil {
+		return err
+	}
+	p.Path = path
+	p.track = nil
+	return nil
 }
 
 func NewPlayerFromPlayer(player *Player) *Player {
@@ -25,21 +93,28 @@ func NewPlayerFromPlayer(player *Player) *Player {
 	return &s
 }
 
+// Clone returns a copy of the player with its own skate path.
+func (p *Player) Clone() *Player {
+	c := *p
+	if p.Path != nil {
+		c.Path = p.Path.ClonePath()
+	}
+	c.track = nil
+	c.Tracking = p.Tracking.Clone()
+	if p.Skating != nil {
+		limits := *p.Skating
+		c.Skating = &limits
+	}
+	return &c
+}
+
 func NewPlayerFromImage(img *ebiten.Image) *Player {
 
 	// Clone an image but only with alpha values.
 	// This is used to detect a user cursor touches the image.
-	b := img.Bounds()
-	alphaImg := image.NewAlpha(b)
-	for j := b.Min.Y; j < b.Max.Y; j++ {
-		for i := b.Min.X; i < b.Max.X; i++ {
-			alphaImg.Set(i, j, img.At(i, j))
-		}
-	}
-
 	return &Player{
 		image:      img,
-		alphaImage: alphaImg,
+		alphaImage: alphaImageOf(img),
 	}
 }
 
@@ -48,30 +123,154 @@ func (p *Player) CopyImagesFrom(player *Player) {
 	p.image = player.image
 }
 
+// label is what is written on the player's sprite.
+func (p *Player) label() string {
+	return spriteLabel(p.Symbol, p.Number)
+}
+
 func (p *Player) CenterPoint() image.Point {
 	sz := p.image.Bounds().Size()
 	return image.Pt(p.X+sz.X/2, p.Y+sz.Y/2)
 }
 
+// StartCentre is where the player's centre is at the start of the frame.
+func (p *Player) StartCentre() SkatePoint {
+	if t := p.tracking(); t != nil {
+		centre, _ := t.pose(t.restTarget())
+		return centre
+	}
+	if p.Path != nil {
+		return p.skateTrack().line.Interpolate(0)
+	}
+	return SkatePoint{X: float32(p.X + playerRadius), Y: float32(p.Y + playerRadius)}
+}
+
+// skateTrack returns the player's Path flattened and planned, reworking it
+// only when Path or the player's limits change.  Editing Path in place needs
+// pathEdited to be called.
+func (p *Player) skateTrack() *skateTrack {
+	limits := p.Limits()
+	if t := p.track; t != nil && t.path == p.Path && t.limits == limits {
+		return t
+	}
+	line := &SkatePath{Points: p.Path.Polyline()}
+	p.track = &skateTrack{
+		path:       p.Path,
+		limits:     limits,
+		line:       line,
+		segments:   p.Path.Segments(),
+		trajectory: NewTrajectory(line.Points, limits),
+	}
+	return p.track
+}
+
+// pathEdited drops what was worked out from the player's Path after it has
+// been reshaped in place.
+func (p *Player) pathEdited() {
+	p.track = nil
+}
+
+// Limits returns how hard the player skates.
+func (p *Player) Limits() SkaterLimits {
+	if p.Skating != nil {
+		return *p.Skating
+	}
+	return DefaultSkaterLimits
+}
+
+// skateSeconds is how long the player's skate takes at their limits, and
+// how long their timing gives it in a frame lasting frameSeconds.  Both are
+// 0 when they have no skate.
+func (p *Player) skateSeconds(frameSeconds float64) (need, have float32) {
+	if p.Path == nil || p.tracking() != nil {
+		return 0, 0
+	}
+	if t := p.skateTrack().trajectory; t != nil {
+		need = t.Seconds
+	}
+	return need, float32(frameSeconds) * max(0, p.Timing.finish()-p.Timing.Delay)
+}
+
+// pathFraction is how far along their skate path the player is at fraction
+// of the frame, allowing for when they skate and how they ease.
+func (p *Player) pathFraction(fraction float32) float32 {
+	f := p.Timing.skateFraction(fraction)
+	if p.Timing.Ease == EaseSkate {
+		return p.skateTrack().trajectory.LengthFraction(f)
+	}
+	return p.Timing.Ease.Apply(f)
+}
+
+// CentreAt is where the player's centre is at fraction of the frame.  Unlike
+// Interpolate it leaves the player where it is.  Tracking goalies are
+// squared up to their Target, or straight out when following the puck, see
+// frame.poseAt.
+func (p *Player) CentreAt(fraction float32) SkatePoint {
+	if p.tracking() == nil && p.Path != nil {
+		return p.skateTrack().line.Interpolate(p.pathFraction(fraction))
+	}
+	return p.StartCentre()
+}
+
+// HeadingAt is the way the player faces at fraction of the frame, along
+// their path or against it on segments they skate backwards.
+func (p *Player) HeadingAt(fraction float32) float32 {
+	if t := p.tracking(); t != nil {
+		_, heading := t.pose(t.restTarget())
+		return heading
+	}
+	if p.Path == nil {
+		return p.Heading
+	}
+	t := p.skateTrack()
+	f := p.pathFraction(fraction)
+	heading := headingAlong(t.line.Points, f)
+	if p.Path.BackwardsOn(segmentAt(t.segments, f)) {
+		heading = reverseHeading(heading)
+	}
+	return heading
+}
+
+// pathStrokes draws the player's path in diagram notation.
+func (p *Player) pathStrokes() []stroke {
+	t := p.skateTrack()
+	return pathStrokes(t.line.Points, t.segments, p.Path.BackwardsOn, p.Style)
+}
+
+// Interpolate moves and turns the player to where they have skated to at
+// fraction of the frame, see Trajectory.
 func (s *Player) Interpolate(fraction float32) {
-	if s.SkatePath != nil {
-		pt := s.SkatePath.Interpolate(fraction)
-		s.X, s.Y = int(pt.X), int(pt.Y)
-		sz := s.image.Bounds().Size()
-		s.X -= sz.X / 2
-		s.Y -= sz.Y / 2
+	if s.Path != nil || s.tracking() != nil {
+		s.Heading = s.HeadingAt(fraction)
+		s.moveCentreTo(s.CentreAt(fraction))
 	}
 }
 
+// moveCentreTo moves the player's sprite to be centred on pt.
+func (s *Player) moveCentreTo(pt SkatePoint) {
+	sz := s.image.Bounds().Size()
+	s.X, s.Y = int(pt.X)-sz.X/2, int(pt.Y)-sz.Y/2
+}
+
 func (s *Player) Draw(screen *ebiten.Image) {
-	if s.SkatePath != nil {
-		s.SkatePath.Draw(screen)
+	if s.Path != nil {
+		drawStrokes(screen, s.pathStrokes(), pathColor)
 	}
 	s.DrawWithAlpha(screen, 1)
 }
 
-// DrawWithAlpha draws the sprite.
+// DrawWithAlpha draws the sprite with which way it faces.
 func (s *Player) DrawWithAlpha(screen *ebiten.Image, alpha float32) {
+	s.drawSprite(screen, alpha)
+	if s.Role == RoleMarker {
+		return
+	}
+	c := s.CenterPoint()
+	drawFacing(screen, SkatePoint{X: float32(c.X), Y: float32(c.Y)}, s.Heading, s.Shoots, alpha)
+}
+
+// drawSprite draws just the sprite, as on the palette.
+func (s *Player) drawSprite(screen *ebiten.Image, alpha float32) {
 	op := &ebiten.DrawImageOptions{}
 	op.GeoM.Translate(float64(s.X), float64(s.Y))
 	op.ColorScale.ScaleAlpha(alpha)
@@ -15,12 +15,25 @@ func (p *PlayerGroup) CloneForNewFrame() *PlayerGroup {
 	for _, p := range p.Players {
 		player := *p
 		player.Interpolate(1)
-		player.SkatePath = nil // Clear the skate path for new frame
+		player.Path = nil // Clear the skate path for new frame
+		player.Timing = SkateTiming{}
+		player.Style = PathStyle{}
+		player.track = nil
+		player.Tracking = p.Tracking.Clone()
 		ret.Players = append(ret.Players, &player)
 	}
 	return ret
 }
 
+// Clone returns a deep copy of the group.
+func (p *PlayerGroup) Clone() *PlayerGroup {
+	ret := &PlayerGroup{}
+	for _, player := range p.Players {
+		ret.Players = append(ret.Players, player.Clone())
+	}
+	return ret
+}
+
 func (p *PlayerGroup) Add(player *Player) {
 	p.Players = append(p.Players, player)
 }
@@ -43,6 +56,16 @@ func (p *PlayerGroup) Draw(screen *ebiten.Image) {
 	}
 }
 
+// ById returns the player with id, or nil.
+func (p *PlayerGroup) ById(id int) *Player {
+	for _, player := range p.Players {
+		if player.Id == id {
+			return player
+		}
+	}
+	return nil
+}
+
 func (p *PlayerGroup) Under(x, y int) *Player {
 	for _, player := range p.Players {
 		if player.In(x, y) {
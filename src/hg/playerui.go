@@ -0,0 +1,339 @@
+package hg
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+
+	"github.com/ebitengine/debugui"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+var selectedColor = color.RGBA{0xf0, 0xb0, 0x00, 0xff}
+
+// rushedColor marks skates quicker than their players' limits allow.
+var rushedColor = color.RGBA{0xf0, 0x70, 0x00, 0xff}
+
+// selectedPlayer returns the selected player in the active frame, or nil.
+func (g *Game) selectedPlayer() *Player {
+	if g.selectedId < 0 {
+		return nil
+	}
+	return g.activeFrame().Players.ById(g.selectedId)
+}
+
+// selectAt selects the player at (x, y).  Clicking empty ice clears the selection.
+func (g *Game) selectAt(x, y int) {
+	if p := g.activeFrame().Players.Under(x, y); p != nil {
+		g.selectedId = p.Id
+	} else if activeRink.OnIce(x, y) {
+		g.selectedId = -1
+	}
+}
+
+// editSelectedPath lets the selected player's path be reshaped, if it is an
+// EditablePath, and reports whether it took the drag in progress.
+func (g *Game) editSelectedPath() bool {
+	p := g.selectedPlayer()
+	if p == nil || g.playback.Playing {
+		return false
+	}
+	if g.mouseController.DragStart() && g.uiHasPointer {
+		return false
+	}
+	path, ok := p.Path.(EditablePath)
+	if !ok {
+		return false
+	}
+	path.UpdateForEdit(g.mouseController, &g.snapper)
+	if !path.Editing() {
+		return false
+	}
+	g.nameDragEdit("Edit path")
+	p.pathEdited()
+	return true
+}
+
+// editSelected changes the selected player as one undoable edit called name.
+func (g *Game) editSelected(name string, f func(p *Player)) {
+	p := g.selectedPlayer()
+	if p == nil {
+		return
+	}
+	g.playback.Playing = false
+	g.recordFieldEdit(fmt.Sprintf("%s of %s", name, p.Symbol), func() { f(p) })
+}
+
+// playerWindow edits when the selected player skates their path, how they
+// ease, how it is drawn, and how hard they skate.
+func (g *Game) playerWindow(ctx *debugui.Context) {
+	p := g.selectedPlayer()
+	if p == nil {
+		return
+	}
+	ctx.Window("Player", image.Rect(880, 609, 1295, 795), func(layout debugui.ContainerLayout) {
+		who := p.Symbol
+		if p.Number > 0 {
+			who += fmt.Sprintf(" #%d", p.Number)
+		}
+		if p.Name != "" {
+			who = p.Name + ", " + who
+		}
+		ctx.Text(fmt.Sprintf("%s, %s, Id %d", who, g.roster.teamName(p.Team), p.Id))
+		duration := g.activeFrame().DurationSeconds
+		if p.Path != nil && duration > 0 {
+			start := float64(p.Timing.Delay) * duration
+			end := float64(p.Timing.finish()) * duration
+			ctx.SetGridLayout([]int{-1, -1, -1, -1}, nil)
+			ctx.Text("Start (s)")
+			ctx.NumberFieldF(&start, 0.05, 2).On(func() {
+				g.editSelected("Start", func(p *Player) {
+					p.Timing.Delay = float32(min(max(start/duration, 0), float64(p.Timing.finish())-0.01))
+				})
+			})
+			ctx.Text("Arrive (s)")
+			ctx.NumberFieldF(&end, 0.05, 2).On(func() {
+				g.editSelected("Arrive", func(p *Player) {
+					p.Timing.Finish = float32(max(min(end/duration, 1), float64(p.Timing.Delay)+0.01))
+				})
+			})
+			ctx.SetGridLayout([]int{-1, -1, -1, -1, -1}, nil)
+			for _, ease := range EaseCurves {
+				label := ease.String()
+				if ease == p.Timing.Ease {
+					label = "[" + label + "]"
+				}
+				ctx.IDScope(ease.String(), func() {
+					ctx.Button(label).On(func() {
+						g.editSelected("Ease", func(p *Player) { p.Timing.Ease = ease })
+					})
+				})
+			}
+			ctx.SetGridLayout([]int{-1, -1}, nil)
+			ctx.Button("Rounded corners").On(func() {
+				g.editSelected("Rounded corners", func(p *Player) {
+					var rp *SkatePathWithRadius
+					switch path := p.Path.(type) {
+					case *SkatePathWithRadius:
+						return
+					case *SplinePath:
+						rp = path.ToRadiusPath()
+					default:
+						rp = SimplifyStroke(path.Polyline(), SimplifyTolerance)
+					}
+					carryFacing(p.Path, rp)
+					p.Path = rp
+				})
+			})
+			ctx.Button("Smooth curve").On(func() {
+				g.editSelected("Smooth curve", func(p *Player) {
+					switch path := p.Path.(type) {
+					case *SplinePath:
+					case *SkatePathWithRadius:
+						p.Path = SplineFromRadiusPath(path)
+					default:
+						s := SplineFromStroke(path.Polyline())
+						carryFacing(path, s)
+						p.Path = s
+					}
+				})
+			})
+			ctx.SetGridLayout([]int{-2, -1, -1, -1, -1, -1}, nil)
+			ctx.Text("Backwards")
+			for i := range len(p.Path.Segments()) - 1 {
+				label := strconv.Itoa(i + 1)
+				if p.Path.BackwardsOn(i) {
+					label = "[" + label + "]"
+				}
+				ctx.IDScope(strconv.Itoa(i), func() {
+					ctx.Button(label).On(func() {
+						g.editSelected("Backwards", func(p *Player) { p.Path.SetBackwards(i, !p.Path.BackwardsOn(i)) })
+					})
+				})
+			}
+			ctx.SetGridLayout([]int{-2, -1, -1, -1}, nil)
+			ctx.Text("Line")
+			for _, line := range PathNotations {
+				label := line.String()
+				if line == p.Style.Line {
+					label = "[" + label + "]"
+				}
+				ctx.IDScope("line"+line.String(), func() {
+					ctx.Button(label).On(func() {
+						g.editSelected("Line", func(p *Player) { p.Style.Line = line })
+					})
+				})
+			}
+			ctx.SetGridLayout([]int{-2, -1, -1, -1}, nil)
+			ctx.Text("End")
+			for _, end := range PathEnds {
+				label := end.String()
+				if end == p.Style.End {
+					label = "[" + label + "]"
+				}
+				ctx.IDScope("end"+end.String(), func() {
+					ctx.Button(label).On(func() {
+						g.editSelected("End", func(p *Player) { p.Style.End = end })
+					})
+				})
+			}
+		} else {
+			ctx.Text("Drag in Skate mode to give them a path.")
+			if p.Role == RoleGoalie {
+				g.creaseControls(ctx, p)
+			}
+			if p.tracking() == nil {
+				degrees := float64(p.Heading) * 180 / math.Pi
+				ctx.SetGridLayout([]int{-1, -1}, nil)
+				ctx.Text("Facing (deg)")
+				ctx.NumberFieldF(&degrees, 5, 0).On(func() {
+					g.editSelected("Facing", func(p *Player) {
+						p.Heading = float32(math.Mod(math.Mod(degrees, 360)+360, 360) * math.Pi / 180)
+					})
+				})
+			}
+		}
+		ctx.SetGridLayout([]int{-2, -1, -1}, nil)
+		ctx.Text("Stick side")
+		for _, side := range []string{ShootsLeft, ShootsRight} {
+			label := side
+			if side == p.Shoots || side == ShootsLeft && p.Shoots == "" {
+				label = "[" + side + "]"
+			}
+			ctx.IDScope("shoots"+side, func() {
+				ctx.Button(label).On(func() {
+					g.editSelected("Stick side", func(p *Player) { p.Shoots = side })
+				})
+			})
+		}
+
+		limits := p.Limits()
+		maxSpeed, accel, brake, turn := float64(limits.MaxSpeed), float64(limits.Accel), float64(limits.Brake), float64(limits.MaxTurnAccel)
+		setLimits := func(name string) {
+			g.editSelected(name, func(p *Player) {
+				p.Skating = &SkaterLimits{
+					MaxSpeed:     float32(max(maxSpeed, 0.1)),
+					Accel:        float32(max(accel, 0.1)),
+					Brake:        float32(max(brake, 0.1)),
+					MaxTurnAccel: float32(max(turn, 0)),
+				}
+			})
+		}
+		ctx.SetGridLayout([]int{-1, -1, -1, -1}, nil)
+		ctx.Text("Speed m/s")
+		ctx.NumberFieldF(&maxSpeed, 0.1, 1).On(func() { setLimits("Speed") })
+		ctx.Text("Accel m/s2")
+		ctx.NumberFieldF(&accel, 0.1, 1).On(func() { setLimits("Acceleration") })
+		ctx.Text("Brake m/s2")
+		ctx.NumberFieldF(&brake, 0.1, 1).On(func() { setLimits("Braking") })
+		ctx.Text("Turn m/s2")
+		ctx.NumberFieldF(&turn, 0.1, 1).On(func() { setLimits("Turning") })
+		ctx.SetGridLayout(nil, nil)
+		if need, have := p.skateSeconds(g.activeFrame().DurationSeconds); need > have {
+			ctx.Text(fmt.Sprintf("Too fast: skate needs %.1fs, has %.1fs", need, have))
+		}
+		if p.Skating != nil {
+			ctx.Button("Default skating").On(func() {
+				g.editSelected("Skating", func(p *Player) { p.Skating = nil })
+			})
+		}
+	})
+}
+
+// creaseControls choose whether a goalie stays put, follows the puck round
+// their crease or squares up to a fixed point.
+func (g *Game) creaseControls(ctx *debugui.Context, p *Player) {
+	modes := []struct {
+		label string
+		on    bool
+		set   func(p *Player)
+	}{
+		{"stay", p.Tracking == nil, func(p *Player) { p.Tracking = nil }},
+		{"puck", p.Tracking != nil && p.Tracking.Target == nil, func(p *Player) {
+			p.Tracking = &GoalieTracking{Net: nearestNet(p.StartCentre())}
+		}},
+		{"point", p.Tracking != nil && p.Tracking.Target != nil, func(p *Player) {
+			t := &GoalieTracking{Net: nearestNet(p.StartCentre())}
+			target := t.restTarget()
+			t.Target = &target
+			p.Tracking = t
+		}},
+	}
+	ctx.SetGridLayout([]int{-2, -1, -1, -1}, nil)
+	ctx.Text("Crease")
+	for _, mode := range modes {
+		label := mode.label
+		if mode.on {
+			label = "[" + label + "]"
+		}
+		ctx.IDScope("crease"+mode.label, func() {
+			ctx.Button(label).On(func() {
+				if !mode.on {
+					g.editSelected("Crease", mode.set)
+				}
+			})
+		})
+	}
+	if t := p.Tracking; t != nil && t.Target != nil {
+		x, y := float64(t.Target.X), float64(t.Target.Y)
+		ctx.SetGridLayout([]int{-1, -1, -1, -1}, nil)
+		ctx.Text("Point x")
+		ctx.NumberFieldF(&x, 5, 0).On(func() {
+			g.editSelected("Crease point", func(p *Player) { p.Tracking.Target.X = float32(x) })
+		})
+		ctx.Text("Point y")
+		ctx.NumberFieldF(&y, 5, 0).On(func() {
+			g.editSelected("Crease point", func(p *Player) { p.Tracking.Target.Y = float32(y) })
+		})
+	}
+}
+
+// drawSelection rings the selected player, marks when they skate on the
+// timeline, numbers the segments of their path and marks a goalie's point.
+func (g *Game) drawSelection(screen *ebiten.Image) {
+	p := g.selectedPlayer()
+	if p == nil {
+		return
+	}
+	centre := p.CenterPoint()
+	vector.StrokeCircle(screen, float32(centre.X), float32(centre.Y), playerRadius+5, 2, selectedColor, true)
+	if t := p.tracking(); t != nil && t.Target != nil {
+		// Mark the point the goalie squares up to.
+		x, y := t.Target.X, t.Target.Y
+		vector.StrokeLine(screen, x-5, y-5, x+5, y+5, 2, selectedColor, true)
+		vector.StrokeLine(screen, x-5, y+5, x+5, y-5, 2, selectedColor, true)
+	}
+	if p.Path != nil {
+		start := frameStartTime(g.frames, g.activeFrameIndex)
+		duration := g.activeFrame().DurationSeconds
+		g.timeline.DrawSpan(screen, g.frames, start+float64(p.Timing.Delay)*duration, start+float64(p.Timing.finish())*duration, selectedColor)
+		// Number the segments to match the Backwards buttons.
+		if segments := p.Path.Segments(); len(segments) > 2 {
+			for i := range len(segments) - 1 {
+				mid := p.Path.Interpolate((segments[i] + segments[i+1]) / 2)
+				ebitenutil.DebugPrintAt(screen, strconv.Itoa(i+1), int(mid.X)+6, int(mid.Y)+2)
+			}
+		}
+	}
+}
+
+// drawRushed marks on the timeline each skate that takes longer at its
+// player's limits than its frame gives it.
+func (g *Game) drawRushed(screen *ebiten.Image) {
+	start := 0.0
+	for _, f := range g.frames {
+		if f.DurationSeconds > 0 {
+			for _, p := range f.Players.Players {
+				if need, have := p.skateSeconds(f.DurationSeconds); need > have {
+					from := start + float64(p.Timing.Delay)*f.DurationSeconds
+					g.timeline.DrawSpan(screen, g.frames, from, start+float64(p.Timing.finish())*f.DurationSeconds, rushedColor)
+				}
+			}
+		}
+		start += f.DurationSeconds
+	}
+}
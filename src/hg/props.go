@@ -0,0 +1,195 @@
+package hg
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+
+	"github.com/hajimehoshi/ebiten/examples/resources/fonts"
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/text/v2"
+)
+
+// PropKind is the kind of object a Prop is.
+type PropKind string
+
+const (
+	PropCone  PropKind = "cone"
+	PropPucks PropKind = "pucks"
+	// PropNet is a spare net with its mouth facing left.
+	PropNet   PropKind = "net"
+	PropTire  PropKind = "tire"
+	PropCoach PropKind = "coach"
+)
+
+// PropKinds lists every kind of prop in the order of the palette.
+var PropKinds = []PropKind{PropCone, PropPucks, PropNet, PropTire, PropCoach}
+
+// Prop is a cone, puck pile or other object placed on the rink for a frame.
+// Like a player, X and Y are the top left of its sprite.
+type Prop struct {
+	image      *ebiten.Image
+	alphaImage *image.Alpha
+	Kind       PropKind
+	X, Y       int
+}
+
+// propLook is how a kind of prop is drawn, shared by the editor and SVG export.
+type propLook struct {
+	w, h  int
+	parts []shape
+	// label is written in the middle in the line colour of the last part.
+	label string
+}
+
+var (
+	coneColor  = color.RGBA{0xf0, 0x70, 0x00, 0xff}
+	netColor   = color.RGBA{0xd0, 0x10, 0x10, 0xff}
+	meshColor  = color.RGBA{0xc0, 0xc0, 0xc0, 0x80}
+	tireColor  = color.RGBA{0x30, 0x30, 0x30, 0xff}
+	coachColor = color.RGBA{0x20, 0x20, 0x20, 0xff}
+)
+
+var propLooks = map[PropKind]propLook{
+	PropCone: {w: 20, h: 20, parts: []shape{
+		{points: []SkatePoint{{X: 10, Y: 1}, {X: 19, Y: 19}, {X: 1, Y: 19}}, closed: true, fill: coneColor},
+	}},
+	PropPucks: {w: 24, h: 20, parts: []shape{
+		{points: circlePoints(SkatePoint{X: 6, Y: 14}, 5), closed: true, fill: puckColor, line: color.RGBA{0xff, 0xff, 0xff, 0xff}, width: 1},
+		{points: circlePoints(SkatePoint{X: 17, Y: 14}, 5), closed: true, fill: puckColor, line: color.RGBA{0xff, 0xff, 0xff, 0xff}, width: 1},
+		{points: circlePoints(SkatePoint{X: 11.5, Y: 6}, 5), closed: true, fill: puckColor, line: color.RGBA{0xff, 0xff, 0xff, 0xff}, width: 1},
+	}},
+	PropNet: {w: 18, h: 40, parts: []shape{
+		{points: []SkatePoint{{X: 2, Y: 2}, {X: 11, Y: 5}, {X: 16, Y: 12}, {X: 16, Y: 28}, {X: 11, Y: 35}, {X: 2, Y: 38}},
+			fill: meshColor, line: netColor, width: 3},
+	}},
+	PropTire: {w: 28, h: 28, parts: []shape{
+		{points: circlePoints(SkatePoint{X: 14, Y: 14}, 13), closed: true, fill: tireColor},
+		{points: circlePoints(SkatePoint{X: 14, Y: 14}, 6), closed: true, fill: color.RGBA{0xc0, 0xc0, 0xc0, 0xff}},
+	}},
+	PropCoach: {w: 28, h: 28, label: "C", parts: []shape{
+		{points: []SkatePoint{{X: 1, Y: 1}, {X: 27, Y: 1}, {X: 27, Y: 27}, {X: 1, Y: 27}}, closed: true,
+			fill: color.RGBA{0xff, 0xff, 0xff, 0xff}, line: coachColor, width: 2},
+	}},
+}
+
+// NewProp makes a prop of kind with its sprite, at the origin.
+func NewProp(kind PropKind) *Prop {
+	look := propLooks[kind]
+	img := ebiten.NewImage(look.w, look.h)
+	drawShapes(img, look.parts)
+	if look.label != "" {
+		if s, err := text.NewGoTextFaceSource(bytes.NewReader(fonts.MPlus1pRegular_ttf)); err == nil {
+			face := &text.GoTextFace{Source: s, Size: float64(look.h) / 2}
+			w, h := text.Measure(look.label, face, 0)
+			op := &text.DrawOptions{}
+			op.GeoM.Translate(float64(look.w)/2-w/2, float64(look.h)/2-h/2)
+			op.ColorScale.ScaleWithColor(look.parts[len(look.parts)-1].line)
+			text.Draw(img, look.label, face, op)
+		}
+	}
+	return &Prop{image: img, alphaImage: alphaImageOf(img), Kind: kind}
+}
+
+// makeFixedProps builds the palette of props that are dragged onto the rink.
+func makeFixedProps() []*Prop {
+	var fixed []*Prop
+	x := 530
+	for _, kind := range PropKinds {
+		prop := NewProp(kind)
+		prop.X = x
+		prop.Y = 630 - propLooks[kind].h/2
+		x += propLooks[kind].w + 12
+		fixed = append(fixed, prop)
+	}
+	return fixed
+}
+
+// bindPropImages gives loaded props the sprite of the matching fixed prop.
+func bindPropImages(frames []frame, fixedProps []*Prop) {
+	byKind := map[PropKind]*Prop{}
+	for _, prop := range fixedProps {
+		byKind[prop.Kind] = prop
+	}
+	for _, frame := range frames {
+		for _, prop := range frame.Props {
+			if fixed := byKind[prop.Kind]; fixed != nil {
+				prop.image, prop.alphaImage = fixed.image, fixed.alphaImage
+			}
+		}
+	}
+}
+
+// CenterPoint is the middle of the prop's sprite.
+func (p *Prop) CenterPoint() image.Point {
+	look := propLooks[p.Kind]
+	return image.Pt(p.X+look.w/2, p.Y+look.h/2)
+}
+
+// Clone returns a copy of the prop sharing its sprite.
+func (p *Prop) Clone() *Prop {
+	c := *p
+	return &c
+}
+
+// cloneProps returns copies of props.
+func cloneProps(props []*Prop) []*Prop {
+	if props == nil {
+		return nil
+	}
+	ret := make([]*Prop, len(props))
+	for i, prop := range props {
+		ret[i] = prop.Clone()
+	}
+	return ret
+}
+
+// propUnder returns the topmost of props at (x, y), or nil.
+func propUnder(props []*Prop, x, y int) *Prop {
+	for i := len(props) - 1; i >= 0; i-- {
+		if props[i].In(x, y) {
+			return props[i]
+		}
+	}
+	return nil
+}
+
+// In reports whether (x, y) is on the prop's sprite, see Player.In.
+func (p *Prop) In(x, y int) bool {
+	return p.alphaImage != nil && p.alphaImage.At(x-p.X, y-p.Y).(color.Alpha).A > 0
+}
+
+// Draw draws the prop's sprite.
+func (p *Prop) Draw(screen *ebiten.Image, alpha float32) {
+	if p.image == nil {
+		return
+	}
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(p.X), float64(p.Y))
+	op.ColorScale.ScaleAlpha(alpha)
+	screen.DrawImage(p.image, op)
+}
+
+// drawProps draws props in order, so later ones are on top.
+func drawProps(screen *ebiten.Image, props []*Prop) {
+	for _, prop := range props {
+		prop.Draw(screen, 1)
+	}
+}
+
+// writeSVGProp draws the same shapes as NewProp.
+func writeSVGProp(w io.Writer, p *Prop) {
+	look := propLooks[p.Kind]
+	fmt.Fprintf(w, `<g transform="translate(%d,%d)">`, p.X, p.Y)
+	writeSVGShapes(w, look.parts)
+	if look.label != "" {
+		var label bytes.Buffer
+		xml.EscapeText(&label, []byte(look.label))
+		fmt.Fprintf(w, `<text x="%g" y="%g" font-family="sans-serif" font-size="%d" fill="%s" text-anchor="middle" dominant-baseline="central">%s</text>`,
+			float32(look.w)/2, float32(look.h)/2, look.h/2, svgColor(look.parts[len(look.parts)-1].line), label.String())
+	}
+	fmt.Fprintln(w, `</g>`)
+}
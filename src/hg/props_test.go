@@ -0,0 +1,41 @@
+package hg
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProps(t *testing.T) {
+	d, err := ParseDrill([]byte(`{"Version": 4, "NextPlayerId": 0, "Frames": [{"DurationSeconds": 1, "Players": {"Players": []},
+	 "Puck": null, "Props": [{"Kind": "cone", "X": 400, "Y": 300}, {"Kind": "net", "X": 90, "Y": 280}]}]}`))
+	require.NoError(t, err)
+	props := d.Frames[0].Props
+	require.Len(t, props, 2)
+	assert.Equal(t, Prop{Kind: PropNet, X: 90, Y: 280}, *props[1])
+
+	// Props are copied with the frame they are on.
+	copied := cloneFrames(d.Frames)
+	copied[0].Props[0].X = 10
+	assert.Equal(t, 400, props[0].X)
+
+	var svg bytes.Buffer
+	require.NoError(t, WriteDrillSVG(&svg, d, SVGOptions{VisibleFrame: -1}))
+	assert.Contains(t, svg.String(), `<g transform="translate(400,300)"><polygon fill="#f07000"`)
+
+	for _, kind := range PropKinds {
+		look := propLooks[kind]
+		for _, part := range look.parts {
+			for _, p := range part.points {
+				assert.True(t, p.X >= 0 && p.Y >= 0 && p.X <= float32(look.w) && p.Y <= float32(look.h), "%s point %v is off its sprite", kind, p)
+			}
+		}
+	}
+
+	d.Frames[0].Props = append(d.Frames[0].Props, &Prop{Kind: "goalie"}, nil)
+	err = d.Validate()
+	assert.ErrorContains(t, err, `frame 1: prop 3 has unknown Kind "goalie"`)
+	assert.ErrorContains(t, err, "frame 1: prop entry 3 is null")
+}
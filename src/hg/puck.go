@@ -0,0 +1,223 @@
+package hg
+
+import (
+	"fmt"
+	"image/color"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+type PuckMoveKind string
+
+const (
+	PuckPass PuckMoveKind = "pass"
+	// PuckSaucer is a pass lifted over sticks, drawn as an arc.
+	PuckSaucer PuckMoveKind = "saucer"
+	PuckShot   PuckMoveKind = "shot"
+)
+
+const puckRadius = 6
+
+// PuckMove sends the puck from whoever has it to another player, or at a
+// target for shots.  Start and End are fractions of the frame's duration.
+type PuckMove struct {
+	Kind   PuckMoveKind
+	ToId   int
+	Target SkatePoint
+	Start  float32
+	End    float32
+}
+
+// Puck is the puck's story through one frame.  It starts with CarrierId, or
+// loose at X, Y when CarrierId is -1, and then follows Moves in order.
+// A carried puck follows its carrier's skate path.
+type Puck struct {
+	CarrierId int
+	X, Y      float32
+	Moves     []PuckMove
+}
+
+// Clone returns a deep copy of the puck.
+func (pk *Puck) Clone() *Puck {
+	if pk == nil {
+		return nil
+	}
+	c := *pk
+	c.Moves = slices.Clone(pk.Moves)
+	return &c
+}
+
+// carriedOffset puts a carried puck on the carrier's stick rather than under them.
+var carriedOffset = SkatePoint{X: playerRadius * 0.8, Y: playerRadius * 0.6}
+
+// holderCentre is where the puck is when held by id, or the loose position if id is -1.
+func holderCentre(players *PlayerGroup, id int, loose SkatePoint, fraction float32) SkatePoint {
+	if p := players.ById(id); p != nil {
+		return p.CentreAt(fraction).Add(carriedOffset)
+	}
+	return loose
+}
+
+// flightPoint is how far along a move's flight the puck is, from 0 to 1.
+func (m *PuckMove) flightPoint(from, to SkatePoint, t float32) SkatePoint {
+	if m.Kind != PuckSaucer {
+		return from.Add(to.Sub(from).Mul(t))
+	}
+	// Quadratic curve bowed to the left of the line of the pass.
+	d := to.Sub(from)
+	control := from.Add(d.Mul(0.5)).Add(SkatePoint{X: d.Y, Y: -d.X}.Mul(0.2))
+	a := from.Add(control.Sub(from).Mul(t))
+	b := control.Add(to.Sub(control).Mul(t))
+	return a.Add(b.Sub(a).Mul(t))
+}
+
+// Position returns where the puck is at fraction of the frame and who has
+// it, -1 if nobody.
+func (pk *Puck) Position(players *PlayerGroup, fraction float32) (SkatePoint, int) {
+	holder := pk.CarrierId
+	loose := SkatePoint{X: pk.X, Y: pk.Y}
+	for i := range pk.Moves {
+		m := &pk.Moves[i]
+		if fraction < m.Start {
+			break
+		}
+		from := holderCentre(players, holder, loose, m.Start)
+		to := m.Target
+		if m.Kind != PuckShot {
+			to = holderCentre(players, m.ToId, loose, m.End)
+		}
+		if fraction < m.End {
+			t := (fraction - m.Start) / (m.End - m.Start)
+			return m.flightPoint(from, to, t), -1
+		}
+		if m.Kind == PuckShot {
+			holder, loose = -1, m.Target
+		} else {
+			holder = m.ToId
+		}
+	}
+	return holderCentre(players, holder, loose, fraction), holder
+}
+
+// AddMove adds m, replacing any moves that start at or after it.  A move
+// still in flight at m's Start arrives then instead, so moves stay in order.
+func (pk *Puck) AddMove(m PuckMove) {
+	pk.Moves = slices.DeleteFunc(pk.Moves, func(old PuckMove) bool { return old.Start >= m.Start })
+	if n := len(pk.Moves); n > 0 && pk.Moves[n-1].End > m.Start {
+		pk.Moves[n-1].End = m.Start
+	}
+	pk.Moves = append(pk.Moves, m)
+}
+
+// ForgetPlayer removes references to a player leaving the frame.  The story
+// stops at the first move they make or receive, as what follows depends on
+// it.  If they had the puck at the start it is left loose where they were.
+func (pk *Puck) ForgetPlayer(p *Player) {
+	holder := pk.CarrierId
+	for i, m := range pk.Moves {
+		if holder == p.Id || m.Kind != PuckShot && m.ToId == p.Id {
+			pk.Moves = pk.Moves[:i]
+			break
+		}
+		holder = m.ToId
+		if m.Kind == PuckShot {
+			holder = -1
+		}
+	}
+	if pk.CarrierId == p.Id {
+		loose := p.StartCentre().Add(carriedOffset)
+		pk.CarrierId, pk.X, pk.Y = -1, loose.X, loose.Y
+	}
+}
+
+// nextFramePuck is the puck at the start of the frame after this one.
+func (pk *Puck) nextFramePuck(players *PlayerGroup) *Puck {
+	pos, holder := pk.Position(players, 1)
+	next := &Puck{CarrierId: holder}
+	if holder < 0 {
+		next.X, next.Y = pos.X, pos.Y
+	}
+	return next
+}
+
+// validate reports problems with the puck in frame n, whose players are byId.
+func (pk *Puck) validate(n int, byId map[int]*Player) []error {
+	var errs []error
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("frame %d: puck: "+format, append([]any{n}, args...)...))
+	}
+	if _, ok := byId[pk.CarrierId]; pk.CarrierId != -1 && !ok {
+		fail("CarrierId %d is not a player in this frame", pk.CarrierId)
+	}
+	prevEnd := float32(0)
+	for i, m := range pk.Moves {
+		switch m.Kind {
+		case PuckPass, PuckSaucer:
+			if _, ok := byId[m.ToId]; !ok {
+				fail("move %d passes to ToId %d, which is not a player in this frame", i, m.ToId)
+			}
+		case PuckShot:
+		default:
+			fail("move %d has unknown Kind %q", i, m.Kind)
+		}
+		if m.Start < prevEnd || m.End < m.Start || m.End > 1 {
+			fail("move %d has Start %g and End %g, moves must be in order within 0 to 1", i, m.Start, m.End)
+		}
+		prevEnd = m.End
+	}
+	return errs
+}
+
+var puckColor = color.RGBA{0x10, 0x10, 0x10, 0xff}
+
+// eachMove calls f with each move and where the puck leaves from and arrives at.
+func (pk *Puck) eachMove(players *PlayerGroup, f func(m *PuckMove, from, to SkatePoint)) {
+	holder := pk.CarrierId
+	loose := SkatePoint{X: pk.X, Y: pk.Y}
+	for i := range pk.Moves {
+		m := &pk.Moves[i]
+		from := holderCentre(players, holder, loose, m.Start)
+		to := m.Target
+		if m.Kind != PuckShot {
+			to = holderCentre(players, m.ToId, loose, m.End)
+		}
+		f(m, from, to)
+		if m.Kind == PuckShot {
+			holder, loose = -1, m.Target
+		} else {
+			holder = m.ToId
+		}
+	}
+}
+
+// flightPoints samples the flight of m from from to to.
+func (m *PuckMove) flightPoints(from, to SkatePoint) []SkatePoint {
+	const steps = 24
+	var pts []SkatePoint
+	for i := 0; i <= steps; i++ {
+		pts = append(pts, m.flightPoint(from, to, float32(i)/steps))
+	}
+	return pts
+}
+
+// Draw draws the puck's passes and shots for the frame and the puck itself at fraction.
+func (pk *Puck) Draw(screen *ebiten.Image, players *PlayerGroup, fraction float32) {
+	pk.eachMove(players, func(m *PuckMove, from, to SkatePoint) {
+		drawStrokes(screen, m.strokes(from, to), puckColor)
+	})
+	pos, _ := pk.Position(players, fraction)
+	vector.DrawFilledCircle(screen, pos.X, pos.Y, puckRadius, puckColor, true)
+}
+
+// strokes draws m from from to to, passes dashed and shots as a double
+// line, each with an arrowhead.
+func (m *PuckMove) strokes(from, to SkatePoint) []stroke {
+	pts := m.flightPoints(from, to)
+	notation := NotationPass
+	if m.Kind == PuckShot {
+		notation = NotationShot
+	}
+	return append(notationStrokes(pts, notation, puckLineWidth), endStrokes(pts, EndArrow, puckLineWidth)...)
+}
@@ -0,0 +1,80 @@
+package hg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPuckPosition(t *testing.T) {
+	players := &PlayerGroup{}
+	players.Add(&Player{Id: 1, X: 0, Y: 0})
+	players.Add(&Player{Id: 2, X: 100, Y: 0})
+	first := SkatePoint{X: 20, Y: 20}.Add(carriedOffset)
+	second := SkatePoint{X: 120, Y: 20}.Add(carriedOffset)
+
+	pk := &Puck{CarrierId: 1}
+	pk.AddMove(PuckMove{Kind: PuckPass, ToId: 2, Start: 0.2, End: 0.4})
+	pk.AddMove(PuckMove{Kind: PuckShot, Target: SkatePoint{X: 500, Y: 300}, Start: 0.6, End: 0.8})
+
+	pos, holder := pk.Position(players, 0.1)
+	assert.Equal(t, first, pos)
+	assert.Equal(t, 1, holder)
+
+	pos, holder = pk.Position(players, 0.3)
+	assert.InDelta(t, (first.X+second.X)/2, pos.X, 0.01)
+	assert.Equal(t, -1, holder)
+
+	pos, holder = pk.Position(players, 0.5)
+	assert.Equal(t, second, pos)
+	assert.Equal(t, 2, holder)
+
+	pos, holder = pk.Position(players, 1)
+	assert.Equal(t, SkatePoint{X: 500, Y: 300}, pos)
+	assert.Equal(t, -1, holder)
+	assert.Equal(t, &Puck{CarrierId: -1, X: 500, Y: 300}, pk.nextFramePuck(players))
+
+	// A new move replaces everything from its start on.
+	pk.AddMove(PuckMove{Kind: PuckSaucer, ToId: 1, Start: 0.5, End: 0.9})
+	assert.Len(t, pk.Moves, 2)
+	assert.Empty(t, pk.validate(1, map[int]*Player{1: nil, 2: nil}))
+
+	// Passing again while the puck is in flight cuts the flight short.
+	pk.AddMove(PuckMove{Kind: PuckPass, ToId: 2, Start: 0.7, End: 0.95})
+	assert.Len(t, pk.Moves, 3)
+	assert.Equal(t, float32(0.7), pk.Moves[1].End)
+	assert.Empty(t, pk.validate(1, map[int]*Player{1: nil, 2: nil}))
+	pk.Moves = pk.Moves[:2]
+
+	// Losing the carrier loses every move, as they make the first.
+	pk.ForgetPlayer(players.ById(1))
+	assert.Empty(t, pk.Moves)
+	assert.Equal(t, -1, pk.CarrierId)
+	assert.Empty(t, pk.validate(1, map[int]*Player{}))
+
+	// Losing a player part way along a chain of passes loses the passes to
+	// and from them and everything after.
+	players.Add(&Player{Id: 3, X: 200, Y: 0})
+	players.Add(&Player{Id: 4, X: 300, Y: 0})
+	chain := func() *Puck {
+		return &Puck{CarrierId: 1, Moves: []PuckMove{
+			{Kind: PuckPass, ToId: 2, Start: 0.1, End: 0.2},
+			{Kind: PuckPass, ToId: 3, Start: 0.3, End: 0.4},
+			{Kind: PuckShot, Target: SkatePoint{X: 500, Y: 300}, Start: 0.5, End: 0.6},
+			{Kind: PuckPass, ToId: 4, Start: 0.7, End: 0.8},
+		}}
+	}
+	pk = chain()
+	pk.ForgetPlayer(players.ById(2))
+	assert.Empty(t, pk.Moves)
+	assert.Equal(t, 1, pk.CarrierId)
+	pk = chain()
+	pk.ForgetPlayer(players.ById(3))
+	assert.Equal(t, chain().Moves[:1], pk.Moves)
+	_, holder = pk.Position(players, 1)
+	assert.Equal(t, 2, holder)
+	// A loose puck picked up later only depends on who picks it up.
+	pk = chain()
+	pk.ForgetPlayer(players.ById(4))
+	assert.Equal(t, chain().Moves[:3], pk.Moves)
+}
@@ -0,0 +1,121 @@
+package hg
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// puckGrab is how close to the puck a drag has to start to pick it up.
+const puckGrab = puckRadius + 4
+
+// handlePuckDrag places the puck, or in pass mode drags passes and shots,
+// and reports whether it took this drag.
+func (g *Game) handlePuckDrag() bool {
+	x, y := g.mouseController.Position()
+	switch {
+	case g.mouseController.DragActive() && g.mouseController.DragStart() && !g.uiHasPointer:
+		g.startPuckDrag(x, y)
+	case g.mouseController.Dropped():
+		switch {
+		case g.draggingPuck:
+			g.dropPuck(x, y)
+		case g.passFrom != nil:
+			g.dropPass(x, y)
+		default:
+			return false
+		}
+		g.draggingPuck, g.passFrom = false, nil
+		return true
+	}
+	return g.draggingPuck || g.passFrom != nil
+}
+
+func (g *Game) startPuckDrag(x, y int) {
+	mouse := SkatePoint{X: float32(x), Y: float32(y)}
+	f := g.activeFrame()
+	if mouse.Sub(puckPalette).Length() <= puckGrab {
+		g.draggingPuck = true
+	} else if f.Puck != nil {
+		pos, holder := f.Puck.Position(f.Players, float32(g.currentTime))
+		under := f.Players.Under(x, y)
+		onPuck := mouse.Sub(pos).Length() <= puckGrab
+		if g.dragMode == dragPass && (onPuck || under != nil && under.Id == holder) {
+			g.passFrom = &pos
+		} else if onPuck {
+			g.draggingPuck = true
+		}
+	}
+	if g.draggingPuck || g.passFrom != nil {
+		g.playback.Playing = false
+	}
+}
+
+// dropPuck gives the puck to the player at (x, y), leaves it loose on the
+// ice or takes it off the rink.
+func (g *Game) dropPuck(x, y int) {
+	f := g.activeFrame()
+	if !activeRink.OnIce(x, y) {
+		if f.Puck != nil {
+			g.nameDragEdit("Remove puck")
+		}
+		f.Puck = nil
+		return
+	}
+	if f.Puck == nil {
+		f.Puck = &Puck{}
+	}
+	if p := f.Players.Under(x, y); p != nil {
+		f.Puck.CarrierId = p.Id
+	} else {
+		f.Puck.CarrierId, f.Puck.X, f.Puck.Y = -1, float32(x), float32(y)
+	}
+	g.nameDragEdit("Move puck")
+}
+
+// dropPass passes to the player at (x, y), or shoots at the net nearer to
+// (x, y) when dropped on open ice, starting now and taking passSeconds.
+func (g *Game) dropPass(x, y int) {
+	f := g.activeFrame()
+	_, holder := f.Puck.Position(f.Players, float32(g.currentTime))
+	drop := SkatePoint{X: float32(x), Y: float32(y)}
+	m := PuckMove{Kind: PuckShot, Target: netCentres()[nearestNet(drop)], Start: float32(g.currentTime)}
+	if p := f.Players.Under(x, y); p != nil {
+		if p.Id == holder {
+			return
+		}
+		m.Kind, m.ToId = PuckPass, p.Id
+		if g.saucer {
+			m.Kind = PuckSaucer
+		}
+	} else if !activeRink.OnIce(x, y) {
+		return
+	}
+	m.End = 1
+	if f.DurationSeconds > 0 {
+		m.End = min(1, m.Start+float32(g.passSeconds/f.DurationSeconds))
+	}
+	f.Puck.AddMove(m)
+	if m.Kind == PuckShot {
+		g.nameDragEdit("Shot")
+	} else {
+		g.nameDragEdit("Pass")
+	}
+}
+
+// drawPuck draws the palette puck, the frame's puck and any puck drag in progress.
+func (g *Game) drawPuck(screen *ebiten.Image) {
+	vector.DrawFilledCircle(screen, puckPalette.X, puckPalette.Y, puckRadius, puckColor, true)
+	f := g.activeFrame()
+	if f.Puck != nil && !g.draggingPuck {
+		f.Puck.Draw(screen, f.Players, float32(g.currentTime))
+	}
+	x, y := g.mouseController.Position()
+	mouse := SkatePoint{X: float32(x), Y: float32(y)}
+	switch {
+	case g.draggingPuck:
+		vector.DrawFilledCircle(screen, mouse.X, mouse.Y, puckRadius, puckColor, true)
+	case g.passFrom != nil:
+		line := []SkatePoint{*g.passFrom, mouse}
+		drawStrokes(screen, append(notationStrokes(line, NotationPass, puckLineWidth), endStrokes(line, EndArrow, puckLineWidth)...), puckColor)
+	}
+}
@@ -0,0 +1,146 @@
+package hg
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// DrillRenderer draws a drill without any of the editor UI, for exports.
+type DrillRenderer struct {
+	drill  *DrillFile
+	rink   *RinkView
+	bounds image.Rectangle
+	target *ebiten.Image
+}
+
+// NewDrillRenderer prepares d for rendering on its rink layout.  The drill's
+// players are animated in place, so d should not be shared with an editor.
+func NewDrillRenderer(d *DrillFile) *DrillRenderer {
+	roster := d.roster()
+	bindPlayerImages(d.Frames, roster, makeFixedPlayers(roster))
+	bindPropImages(d.Frames, makeFixedProps())
+	rink := d.Rink.View()
+	return &DrillRenderer{drill: d, rink: rink, bounds: rink.Bounds()}
+}
+
+// Bounds is the area of the editor screen that is rendered, the part of the
+// rink the drill's layout shows.
+func (r *DrillRenderer) Bounds() image.Rectangle {
+	return r.bounds
+}
+
+// Draw draws frameIndex with its players moved fraction of the way along
+// their skate paths, and the puck.  The drill's rink is the active one while
+// it draws, so skating speeds and goalies follow its scale.
+func (r *DrillRenderer) Draw(dst *ebiten.Image, frameIndex int, fraction float32) {
+	old := activeRink
+	useRink(r.rink)
+	defer useRink(old)
+	dst.Fill(color.White)
+	r.rink.Draw(dst)
+	f := &r.drill.Frames[frameIndex]
+	drawProps(dst, f.Props)
+	f.Interpolate(fraction)
+	f.Players.Draw(dst)
+	if f.Puck != nil {
+		f.Puck.Draw(dst, f.Players, fraction)
+	}
+}
+
+// Render draws a frame into a new RGBA image.  Like all pixel reads it must
+// run inside the ebiten game loop, see RunOffscreen.
+func (r *DrillRenderer) Render(frameIndex int, fraction float32) *image.RGBA {
+	if r.target == nil {
+		r.target = ebiten.NewImage(r.bounds.Max.X, r.bounds.Max.Y)
+	}
+	r.Draw(r.target, frameIndex, fraction)
+	img := image.NewRGBA(image.Rect(0, 0, r.bounds.Dx(), r.bounds.Dy()))
+	r.target.SubImage(r.bounds).(*ebiten.Image).ReadPixels(img.Pix)
+	return img
+}
+
+// Stills returns the (frame, fraction) pairs for a still sequence.  With no
+// steps there is one still per frame showing its starting positions and
+// paths; with steps each frame is sampled that many times and the sequence
+// ends on the final positions.
+func (r *DrillRenderer) Stills(steps int) (frames []int, fractions []float32) {
+	if steps <= 0 {
+		for i := range r.drill.Frames {
+			frames = append(frames, i)
+			fractions = append(fractions, 0)
+		}
+		return frames, fractions
+	}
+	for i := range r.drill.Frames {
+		for step := 0; step < steps; step++ {
+			frames = append(frames, i)
+			fractions = append(fractions, float32(step)/float32(steps))
+		}
+	}
+	frames = append(frames, len(r.drill.Frames)-1)
+	fractions = append(fractions, 1)
+	return frames, fractions
+}
+
+// WritePNGs renders the still sequence into dir as prefix_0001.png onwards
+// and returns the files written.
+func (r *DrillRenderer) WritePNGs(dir, prefix string, steps int) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	var written []string
+	frames, fractions := r.Stills(steps)
+	for i := range frames {
+		name := filepath.Join(dir, fmt.Sprintf("%s_%04d.png", prefix, i+1))
+		f, err := os.Create(name)
+		if err != nil {
+			return written, err
+		}
+		err = png.Encode(f, r.Render(frames[i], fractions[i]))
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return written, fmt.Errorf("%s: %w", name, err)
+		}
+		written = append(written, name)
+	}
+	return written, nil
+}
+
+type offscreenGame struct {
+	job func() error
+	err error
+}
+
+func (o *offscreenGame) Update() error {
+	o.err = o.job()
+	return ebiten.Termination
+}
+
+func (o *offscreenGame) Draw(screen *ebiten.Image) {}
+
+func (o *offscreenGame) Layout(outsideWidth, outsideHeight int) (int, int) {
+	return 1, 1
+}
+
+// RunOffscreen runs job inside the ebiten game loop and returns its error.
+// ebiten needs a graphics context to draw at all, so a tiny undecorated
+// window exists for the duration, but nothing is shown in it.
+func RunOffscreen(job func() error) error {
+	game := &offscreenGame{job: job}
+	ebiten.SetWindowSize(1, 1)
+	ebiten.SetWindowDecorated(false)
+	err := ebiten.RunGameWithOptions(game, &ebiten.RunGameOptions{
+		InitUnfocused: true,
+		SkipTaskbar:   true,
+	})
+	return errors.Join(err, game.err)
+}
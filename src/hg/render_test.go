@@ -0,0 +1,25 @@
+package hg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStills(t *testing.T) {
+	r := &DrillRenderer{drill: &DrillFile{Frames: make([]frame, 2)}}
+	tests := []struct {
+		steps     int
+		frames    []int
+		fractions []float32
+	}{
+		{0, []int{0, 1}, []float32{0, 0}},
+		{1, []int{0, 1, 1}, []float32{0, 0, 1}},
+		{2, []int{0, 0, 1, 1, 1}, []float32{0, 0.5, 0, 0.5, 1}},
+	}
+	for _, tt := range tests {
+		frames, fractions := r.Stills(tt.steps)
+		assert.Equal(t, tt.frames, frames, "steps %d", tt.steps)
+		assert.Equal(t, tt.fractions, fractions, "steps %d", tt.steps)
+	}
+}
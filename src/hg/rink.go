@@ -0,0 +1,337 @@
+package hg
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// foot is a foot in metres.  Rink markings are specified in feet.
+const foot = 0.3048
+
+// rinkPixelW and rinkPixelH are the drill's coordinate space in pixels,
+// which the rink is fitted into.
+const (
+	rinkPixelW = 1280
+	rinkPixelH = 595
+)
+
+// Rink is the size of a sheet of ice and where its markings are, in metres.
+// Points on it are measured from the top left corner of the boards, with x
+// along its length and y across it.
+type Rink struct {
+	Length, Width float32
+	CornerRadius  float32
+	// GoalLine and BlueLine are how far those lines are from the end boards.
+	GoalLine, BlueLine float32
+	FaceoffRadius      float32
+	// EndDot is how far the end zone faceoff dots are out from the goal line
+	// and NeutralDot how far the neutral zone dots are from the blue line.
+	// DotSpread is how far either side of the middle of the ice both are.
+	EndDot, NeutralDot, DotSpread float32
+	CreaseRadius                  float32
+	NetWidth, NetDepth            float32
+}
+
+// NHLRink is a 200 by 85 foot rink.
+func NHLRink() *Rink {
+	return &Rink{
+		Length:        200 * foot,
+		Width:         85 * foot,
+		CornerRadius:  28 * foot,
+		GoalLine:      11 * foot,
+		BlueLine:      75 * foot,
+		FaceoffRadius: 15 * foot,
+		EndDot:        20 * foot,
+		NeutralDot:    5 * foot,
+		DotSpread:     22 * foot,
+		CreaseRadius:  6 * foot,
+		NetWidth:      6 * foot,
+		NetDepth:      40.0 / 12 * foot,
+	}
+}
+
+// IIHFRink is a 60 by 30 metre international rink.
+func IIHFRink() *Rink {
+	return &Rink{
+		Length:        60,
+		Width:         30,
+		CornerRadius:  8.5,
+		GoalLine:      4,
+		BlueLine:      21.23,
+		FaceoffRadius: 4.5,
+		EndDot:        6,
+		NeutralDot:    1.5,
+		DotSpread:     7,
+		CreaseRadius:  1.83,
+		NetWidth:      1.83,
+		NetDepth:      1.12,
+	}
+}
+
+// Validate reports markings that don't fit on the rink.
+func (r *Rink) Validate() error {
+	var errs []error
+	if r.Length <= 0 || r.Width <= 0 {
+		errs = append(errs, fmt.Errorf("rink: Length %g and Width %g must be more than 0", r.Length, r.Width))
+	}
+	if r.CornerRadius < 0 || 2*r.CornerRadius > min(r.Length, r.Width) {
+		errs = append(errs, fmt.Errorf("rink: CornerRadius %g does not fit", r.CornerRadius))
+	}
+	if r.GoalLine <= 0 || r.BlueLine <= r.GoalLine || 2*r.BlueLine >= r.Length {
+		errs = append(errs, fmt.Errorf("rink: GoalLine %g and BlueLine %g must be in that order out from the end and short of centre ice", r.GoalLine, r.BlueLine))
+	}
+	return errors.Join(errs...)
+}
+
+// Centre is the centre ice faceoff dot.
+func (r *Rink) Centre() SkatePoint {
+	return SkatePoint{X: r.Length / 2, Y: r.Width / 2}
+}
+
+// GoalLines are how far along the rink the left and right goal lines are.
+func (r *Rink) GoalLines() [2]float32 {
+	return [2]float32{r.GoalLine, r.Length - r.GoalLine}
+}
+
+// BlueLines are how far along the rink the left and right blue lines are.
+func (r *Rink) BlueLines() [2]float32 {
+	return [2]float32{r.BlueLine, r.Length - r.BlueLine}
+}
+
+// NetCentres are the middles of the left and right goal mouths.
+func (r *Rink) NetCentres() [2]SkatePoint {
+	goals := r.GoalLines()
+	return [2]SkatePoint{{X: goals[0], Y: r.Width / 2}, {X: goals[1], Y: r.Width / 2}}
+}
+
+// EndCircles are the centres of the four end zone faceoff circles, which
+// are also their dots.
+func (r *Rink) EndCircles() []SkatePoint {
+	goals := r.GoalLines()
+	return r.dotPairs(goals[0]+r.EndDot, goals[1]-r.EndDot)
+}
+
+// NeutralDots are the four neutral zone faceoff dots.
+func (r *Rink) NeutralDots() []SkatePoint {
+	blues := r.BlueLines()
+	return r.dotPairs(blues[0]+r.NeutralDot, blues[1]-r.NeutralDot)
+}
+
+// FaceoffDots are all nine faceoff dots.
+func (r *Rink) FaceoffDots() []SkatePoint {
+	return append(append(r.EndCircles(), r.NeutralDots()...), r.Centre())
+}
+
+// hashMarks are the four marks on the top and bottom of the end circle
+// about c where the wingers line up, each from the circle outwards.
+func (r *Rink) hashMarks(c SkatePoint) [][2]SkatePoint {
+	var marks [][2]SkatePoint
+	for _, side := range []float32{-1, 1} {
+		for _, end := range []float32{-1, 1} {
+			x := c.X + side*3*foot
+			y := c.Y + end*float32(math.Sqrt(float64(r.FaceoffRadius*r.FaceoffRadius-9*foot*foot)))
+			marks = append(marks, [2]SkatePoint{{X: x, Y: y}, {X: x, Y: y + end*2*foot}})
+		}
+	}
+	return marks
+}
+
+// dotPairs are dots DotSpread either side of the middle at left and right.
+func (r *Rink) dotPairs(left, right float32) []SkatePoint {
+	top, bottom := r.Width/2-r.DotSpread, r.Width/2+r.DotSpread
+	return []SkatePoint{{X: left, Y: top}, {X: left, Y: bottom}, {X: right, Y: top}, {X: right, Y: bottom}}
+}
+
+// boardInset is how far in from the sides the boards are at x along the
+// rink, which is more than 0 where they round the corners.
+func (r *Rink) boardInset(x float32) float32 {
+	d := min(x, r.Length-x)
+	if d >= r.CornerRadius {
+		return 0
+	}
+	dx := float64(r.CornerRadius - d)
+	return r.CornerRadius - float32(math.Sqrt(float64(r.CornerRadius*r.CornerRadius)-dx*dx))
+}
+
+// Contains reports whether pt is on the ice inside the boards.
+func (r *Rink) Contains(pt SkatePoint) bool {
+	if pt.X < 0 || pt.X > r.Length {
+		return false
+	}
+	inset := r.boardInset(pt.X)
+	return pt.Y >= inset && pt.Y <= r.Width-inset
+}
+
+// boards is the outline of the boards going clockwise from the top left.
+func (r *Rink) boards() []SkatePoint {
+	c := r.CornerRadius
+	var points []SkatePoint
+	for i, corner := range []SkatePoint{{X: c, Y: c}, {X: r.Length - c, Y: c}, {X: r.Length - c, Y: r.Width - c}, {X: c, Y: r.Width - c}} {
+		from := math.Pi + float64(i)*math.Pi/2
+		points = append(points, arcPoints(corner, c, from, from+math.Pi/2, 12)...)
+	}
+	return points
+}
+
+// arcPoints are n+1 points round the arc of radius r about centre between
+// the from and to angles, in radians clockwise from +X.
+func arcPoints(centre SkatePoint, r float32, from, to float64, n int) []SkatePoint {
+	points := make([]SkatePoint, n+1)
+	for i := range points {
+		a := from + (to-from)*float64(i)/float64(n)
+		points[i] = centre.Add(SkatePoint{X: float32(math.Cos(a)), Y: float32(math.Sin(a))}.Mul(r))
+	}
+	return points
+}
+
+// RinkView places the stretch of a Rink a drill shows in the drill's pixel
+// coordinates, as large as fits in an area and centred in it.
+type RinkView struct {
+	Rink *Rink
+	// Scale is pixels per metre and Origin is the rink's (0, 0) in pixels.
+	Scale  float32
+	Origin SkatePoint
+
+	area   image.Rectangle
+	bounds image.Rectangle
+	image  *ebiten.Image
+}
+
+// NewRinkView fits the full width of r from from to to metres along its
+// length into area.
+func NewRinkView(r *Rink, area image.Rectangle, from, to float32) *RinkView {
+	scale := min(float32(area.Dx())/(to-from), float32(area.Dy())/r.Width)
+	v := &RinkView{
+		Rink:  r,
+		Scale: scale,
+		Origin: SkatePoint{
+			X: float32(area.Min.X) + (float32(area.Dx())-(to-from)*scale)/2 - from*scale,
+			Y: float32(area.Min.Y) + (float32(area.Dy())-r.Width*scale)/2,
+		},
+		area: area,
+	}
+	topLeft, bottomRight := v.ToPixels(SkatePoint{X: from}), v.ToPixels(SkatePoint{X: to, Y: r.Width})
+	v.bounds = image.Rect(int(topLeft.X), int(topLeft.Y), int(math.Ceil(float64(bottomRight.X))), int(math.Ceil(float64(bottomRight.Y)))).Intersect(area)
+	return v
+}
+
+// Bounds is the part of the drill's pixels the view shows.
+func (v *RinkView) Bounds() image.Rectangle {
+	return v.bounds
+}
+
+// ToPixels converts a point on the rink in metres to drill pixels.
+func (v *RinkView) ToPixels(pt SkatePoint) SkatePoint {
+	return v.Origin.Add(pt.Mul(v.Scale))
+}
+
+// ToRink converts drill pixels to a point on the rink in metres.
+func (v *RinkView) ToRink(px SkatePoint) SkatePoint {
+	return px.Sub(v.Origin).Mul(1 / v.Scale)
+}
+
+// OnIce reports whether the pixel (x, y) is shown and inside the boards.
+func (v *RinkView) OnIce(x, y int) bool {
+	return image.Pt(x, y).In(v.bounds) && v.Rink.Contains(v.ToRink(SkatePoint{X: float32(x), Y: float32(y)}))
+}
+
+// remapTo is the scale and offset that move drill pixels on v to the same
+// place on to, measured in metres from centre ice.
+func (v *RinkView) remapTo(to *RinkView) (scale float32, offset SkatePoint) {
+	scale = to.Scale / v.Scale
+	offset = to.ToPixels(to.Rink.Centre().Sub(v.Rink.Centre())).Sub(v.Origin.Mul(scale))
+	return scale, offset
+}
+
+var (
+	iceColor    = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	boardsColor = color.RGBA{0x00, 0x00, 0x00, 0xff}
+	redLine     = color.RGBA{0xd0, 0x30, 0x30, 0xff}
+	blueLine    = color.RGBA{0x40, 0x60, 0xc0, 0xff}
+	creaseColor = color.RGBA{0xa8, 0xd8, 0xf0, 0xff}
+)
+
+// shapes are the ice, its markings and the boards in pixels, in drawing order.
+func (v *RinkView) shapes() []shape {
+	r := v.Rink
+	px := func(points ...SkatePoint) []SkatePoint {
+		out := make([]SkatePoint, len(points))
+		for i, p := range points {
+			out[i] = v.ToPixels(p)
+		}
+		return out
+	}
+	// Lines are drawn true to size, but no thinner than 2 pixels.
+	width := func(metres float32) float32 { return max(2, metres*v.Scale) }
+	across := func(x, inset float32, col color.RGBA, w float32) shape {
+		return shape{points: px(SkatePoint{X: x, Y: inset}, SkatePoint{X: x, Y: r.Width - inset}), line: col, width: width(w)}
+	}
+	circle := func(centre SkatePoint, radius float32, col color.RGBA, w float32) shape {
+		return shape{points: px(circlePoints(centre, radius)...), closed: true, line: col, width: width(w)}
+	}
+	dot := func(centre SkatePoint, radius float32, col color.RGBA) shape {
+		return shape{points: px(circlePoints(centre, radius)...), closed: true, fill: col}
+	}
+
+	shapes := []shape{{points: px(r.boards()...), closed: true, fill: iceColor}}
+	for i, x := range r.GoalLines() {
+		shapes = append(shapes, across(x, r.boardInset(x), redLine, 2.0/12*foot))
+		// The crease in front of the net and the net behind the goal line.
+		out := float64(1 - 2*i)
+		net := r.NetCentres()[i]
+		crease := arcPoints(net, r.CreaseRadius, -math.Pi/2, math.Pi/2, 12)
+		if i == 1 {
+			crease = arcPoints(net, r.CreaseRadius, math.Pi/2, 3*math.Pi/2, 12)
+		}
+		shapes = append(shapes, shape{points: px(crease...), closed: true, fill: creaseColor, line: redLine, width: width(2.0 / 12 * foot)})
+		back := net.X - float32(out)*r.NetDepth
+		shapes = append(shapes, shape{points: px(
+			SkatePoint{X: net.X, Y: net.Y - r.NetWidth/2}, SkatePoint{X: back, Y: net.Y - r.NetWidth/2},
+			SkatePoint{X: back, Y: net.Y + r.NetWidth/2}, SkatePoint{X: net.X, Y: net.Y + r.NetWidth/2},
+		), fill: meshColor, line: redLine, width: 2})
+	}
+	for _, x := range r.BlueLines() {
+		shapes = append(shapes, across(x, 0, blueLine, foot))
+	}
+	shapes = append(shapes, across(r.Length/2, 0, redLine, foot))
+	for _, c := range r.EndCircles() {
+		shapes = append(shapes, circle(c, r.FaceoffRadius, redLine, 2.0/12*foot), dot(c, foot, redLine))
+		for _, mark := range r.hashMarks(c) {
+			shapes = append(shapes, shape{points: px(mark[:]...), line: redLine, width: width(2.0 / 12 * foot)})
+		}
+	}
+	for _, d := range r.NeutralDots() {
+		shapes = append(shapes, dot(d, foot, redLine))
+	}
+	shapes = append(shapes,
+		circle(r.Centre(), r.FaceoffRadius, blueLine, 2.0/12*foot),
+		dot(r.Centre(), foot/2, blueLine),
+		// The referee's crease against the bottom boards.
+		shape{points: px(arcPoints(SkatePoint{X: r.Length / 2, Y: r.Width}, 10*foot, math.Pi, 2*math.Pi, 16)...), line: redLine, width: width(2.0 / 12 * foot)},
+		shape{points: px(r.boards()...), closed: true, line: boardsColor, width: 5},
+	)
+	return shapes
+}
+
+// Draw draws the part of the rink the view shows, working out its markings
+// the first time.
+func (v *RinkView) Draw(dst *ebiten.Image) {
+	if v.image == nil {
+		v.image = ebiten.NewImage(v.area.Max.X, v.area.Max.Y)
+		drawShapes(v.image, v.shapes())
+	}
+	op := &ebiten.DrawImageOptions{}
+	op.GeoM.Translate(float64(v.bounds.Min.X), float64(v.bounds.Min.Y))
+	dst.DrawImage(v.image.SubImage(v.bounds).(*ebiten.Image), op)
+}
+
+// writeSVG writes the rink as SVG shapes.
+func (v *RinkView) writeSVG(w io.Writer) {
+	writeSVGShapes(w, v.shapes())
+}
@@ -0,0 +1,100 @@
+package hg
+
+import (
+	"bytes"
+	"image"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRink(t *testing.T) {
+	r := NHLRink()
+	assert.InDelta(t, 60.96, r.Length, 0.01)
+	assert.InDelta(t, 25.91, r.Width, 0.01)
+
+	// The boards round off the corners.
+	assert.True(t, r.Contains(r.Centre()))
+	assert.True(t, r.Contains(SkatePoint{X: r.Length / 2, Y: 0.1}))
+	assert.False(t, r.Contains(SkatePoint{X: 0.5, Y: 0.5}))
+	assert.True(t, r.Contains(SkatePoint{X: r.CornerRadius, Y: 0.1}))
+	assert.False(t, r.Contains(SkatePoint{X: -0.1, Y: r.Width / 2}))
+
+	dots := r.FaceoffDots()
+	require.Len(t, dots, 9)
+	for _, d := range dots {
+		assert.True(t, r.Contains(d), "dot %v is off the ice", d)
+		mirror := SkatePoint{X: r.Length - d.X, Y: r.Width - d.Y}
+		assert.True(t, slices.ContainsFunc(dots, func(o SkatePoint) bool { return o.Sub(mirror).Length() < 0.001 }),
+			"dot %v has no mirror image", d)
+	}
+	nets := r.NetCentres()
+	assert.InDelta(t, 11*foot, nets[0].X, 0.001)
+	assert.InDelta(t, r.Length-11*foot, nets[1].X, 0.001)
+
+	v := NewRinkView(r, image.Rect(0, 0, 1000, 1000), 0, r.Length)
+	assert.InDelta(t, 1000/r.Length, v.Scale, 0.001)
+	centre := v.ToPixels(r.Centre())
+	assert.InDelta(t, 500, centre.X, 0.01)
+	assert.InDelta(t, 500, centre.Y, 0.01)
+	pt := SkatePoint{X: 12.5, Y: 3.25}
+	back := v.ToRink(v.ToPixels(pt))
+	assert.InDelta(t, pt.X, back.X, 0.001)
+	assert.InDelta(t, pt.Y, back.Y, 0.001)
+	assert.True(t, v.OnIce(500, 500))
+	assert.False(t, v.OnIce(500, 100))
+
+	// The SVG draws the rink's markings without a drawing of its own.
+	var buf bytes.Buffer
+	d := &DrillFile{Frames: []frame{{DurationSeconds: 1, Players: &PlayerGroup{}}}}
+	require.NoError(t, WriteDrillSVG(&buf, d, SVGOptions{VisibleFrame: -1}))
+	assert.Contains(t, buf.String(), svgColor(blueLine))
+	assert.Contains(t, buf.String(), svgColor(creaseColor))
+}
+
+func TestRinkLayout(t *testing.T) {
+	full := RinkLayout{}.View()
+	assert.Equal(t, rinkPixelW, full.Bounds().Dx())
+	assert.Equal(t, rinkPixelH-full.Bounds().Max.Y, full.Bounds().Min.Y)
+
+	// Half ice shows the right hand end, with nothing to the left of centre.
+	half := RinkLayout{Size: RinkIIHF, Area: RinkHalf}.View()
+	assert.Equal(t, float32(60), half.Rink.Length)
+	assert.Less(t, half.Bounds().Dx(), rinkPixelW/2+50)
+	centre := half.ToPixels(half.Rink.Centre())
+	assert.True(t, half.OnIce(int(centre.X)+5, int(centre.Y)))
+	assert.False(t, half.OnIce(int(centre.X)-5, int(centre.Y)))
+
+	// Switching layouts keeps everything the same distance from centre ice.
+	p := &Player{Id: 1, X: 400, Y: 200, Path: &SkatePathWithRadius{
+		Points: []SkatePoint{{X: 420, Y: 220}, {X: 700, Y: 220}, {X: 700, Y: 400}}, PointRadiuses: []float32{0, 30, 0}}}
+	frames := []frame{{
+		Players: &PlayerGroup{Players: []*Player{p}},
+		Puck:    &Puck{CarrierId: -1, X: 640, Y: 297, Moves: []PuckMove{{Kind: PuckShot, Target: SkatePoint{X: 1200, Y: 297}}}},
+	}}
+	metres := func(v *RinkView, px SkatePoint) SkatePoint { return v.ToRink(px).Sub(v.Rink.Centre()) }
+	before := metres(full, SkatePoint{X: 700, Y: 400})
+	remapFrames(frames, full, half)
+	path := p.Path.(*SkatePathWithRadius)
+	after := metres(half, path.Points[2])
+	assert.InDelta(t, before.X, after.X, 0.001)
+	assert.InDelta(t, before.Y, after.Y, 0.001)
+	assert.InDelta(t, 30*half.Scale/full.Scale, path.PointRadiuses[1], 0.001)
+	remapFrames(frames, half, full)
+	assert.InDelta(t, 700, path.Points[2].X, 0.01)
+	assert.InDelta(t, 1200, frames[0].Puck.Moves[0].Target.X, 0.01)
+	assert.InDelta(t, 640, frames[0].Puck.X, 0.01)
+	assert.Equal(t, 400, p.X)
+	assert.Equal(t, 200, p.Y)
+
+	assert.NoError(t, RinkLayout{Size: RinkCustom, Custom: NHLRink()}.Validate())
+	err := RinkLayout{Size: "olympic", Area: "corner"}.Validate()
+	assert.ErrorContains(t, err, `rink has unknown Size "olympic"`)
+	assert.ErrorContains(t, err, `rink has unknown Area "corner"`)
+	assert.ErrorContains(t, RinkLayout{Size: RinkCustom}.Validate(), "no Custom rink")
+	small := NHLRink()
+	small.Length = 40
+	assert.ErrorContains(t, RinkLayout{Size: RinkCustom, Custom: small}.Validate(), "short of centre ice")
+}
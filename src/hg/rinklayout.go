@@ -0,0 +1,178 @@
+package hg
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"slices"
+)
+
+// RinkSize is the standard a drill's rink is built to.
+type RinkSize string
+
+const (
+	// RinkNHL is 200 by 85 feet, see NHLRink.
+	RinkNHL RinkSize = ""
+	// RinkIIHF is 60 by 30 metres, see IIHFRink.
+	RinkIIHF RinkSize = "iihf"
+	// RinkCustom is the layout's own Custom rink.
+	RinkCustom RinkSize = "custom"
+)
+
+// RinkSizes lists every size.
+var RinkSizes = []RinkSize{RinkNHL, RinkIIHF, RinkCustom}
+
+func (s RinkSize) String() string {
+	switch s {
+	case RinkNHL:
+		return "NHL"
+	case RinkIIHF:
+		return "IIHF"
+	}
+	return string(s)
+}
+
+// RinkArea is how much of the rink a drill shows.  Half and zone areas are
+// at the right hand end, attacking the right net.
+type RinkArea string
+
+const (
+	RinkFull      RinkArea = ""
+	RinkHalf      RinkArea = "half"
+	RinkOffensive RinkArea = "offensive"
+	RinkNeutral   RinkArea = "neutral"
+)
+
+// RinkAreas lists every area.
+var RinkAreas = []RinkArea{RinkFull, RinkHalf, RinkOffensive, RinkNeutral}
+
+func (a RinkArea) String() string {
+	if a == RinkFull {
+		return "full"
+	}
+	return string(a)
+}
+
+// zoneOverlap is how far past their blue lines zone areas reach, in metres.
+const zoneOverlap = 2
+
+// span is the stretch of r's length the area shows, in metres.
+func (a RinkArea) span(r *Rink) (from, to float32) {
+	switch a {
+	case RinkHalf:
+		return r.Length / 2, r.Length
+	case RinkOffensive:
+		return r.Length - r.BlueLine - zoneOverlap, r.Length
+	case RinkNeutral:
+		return r.BlueLine - zoneOverlap, r.Length - r.BlueLine + zoneOverlap
+	}
+	return 0, r.Length
+}
+
+// RinkLayout is the rink a drill is drawn on and how much of it is shown.
+// The zero value is a full NHL rink.
+type RinkLayout struct {
+	Size RinkSize
+	Area RinkArea
+	// Custom is the rink when Size is RinkCustom, and nil otherwise.
+	Custom *Rink
+}
+
+// clone returns a copy with its own Custom rink.
+func (l RinkLayout) clone() RinkLayout {
+	if l.Custom != nil {
+		custom := *l.Custom
+		l.Custom = &custom
+	}
+	return l
+}
+
+// rink is the rink of the layout's Size.
+func (l RinkLayout) rink() *Rink {
+	switch l.Size {
+	case RinkIIHF:
+		return IIHFRink()
+	case RinkCustom:
+		if l.Custom != nil {
+			custom := *l.Custom
+			return &custom
+		}
+	}
+	return NHLRink()
+}
+
+// View fits the layout into the drill's pixels.
+func (l RinkLayout) View() *RinkView {
+	r := l.rink()
+	from, to := l.Area.span(r)
+	return NewRinkView(r, image.Rect(0, 0, rinkPixelW, rinkPixelH), from, to)
+}
+
+// Validate reports an unknown Size or Area, or a Custom rink that is
+// missing or doesn't fit its markings.
+func (l RinkLayout) Validate() error {
+	var errs []error
+	if !slices.Contains(RinkSizes, l.Size) {
+		errs = append(errs, fmt.Errorf("rink has unknown Size %q", l.Size))
+	}
+	if !slices.Contains(RinkAreas, l.Area) {
+		errs = append(errs, fmt.Errorf("rink has unknown Area %q", l.Area))
+	}
+	switch {
+	case l.Size == RinkCustom && l.Custom == nil:
+		errs = append(errs, errors.New(`rink has Size "custom" but no Custom rink`))
+	case l.Size == RinkCustom:
+		errs = append(errs, l.Custom.Validate())
+	case l.Custom != nil:
+		errs = append(errs, fmt.Errorf("rink has a Custom rink but Size %q", l.Size))
+	}
+	return errors.Join(errs...)
+}
+
+// activeRink is the view the open drill is drawn on.  Skating speeds and
+// goalie creases are worked out on it.
+var activeRink = RinkLayout{}.View()
+
+// useRink makes v the view drills are drawn on.
+func useRink(v *RinkView) {
+	activeRink = v
+}
+
+// remapFrames moves everything in frames from where it is on from to the
+// same place on to, measured in metres from centre ice, so distances and
+// skating speeds are kept when a drill changes layout.
+func remapFrames(frames []frame, from, to *RinkView) {
+	scale, offset := from.remapTo(to)
+	move := func(pt SkatePoint) SkatePoint { return pt.Mul(scale).Add(offset) }
+	// Sprites sit on whole pixels, so round rather than truncate to stop
+	// them drifting as layouts change back and forth.
+	round := func(v float32) int { return int(math.Round(float64(v))) }
+	for _, f := range frames {
+		for _, p := range f.Players.Players {
+			centre := move(SkatePoint{X: float32(p.X + playerRadius), Y: float32(p.Y + playerRadius)})
+			p.X, p.Y = round(centre.X)-playerRadius, round(centre.Y)-playerRadius
+			if p.Path != nil {
+				p.Path.Transform(scale, offset)
+			}
+			p.pathEdited()
+			if t := p.Tracking; t != nil && t.Target != nil {
+				*t.Target = move(*t.Target)
+			}
+		}
+		if pk := f.Puck; pk != nil {
+			if pk.CarrierId == -1 {
+				pt := move(SkatePoint{X: pk.X, Y: pk.Y})
+				pk.X, pk.Y = pt.X, pt.Y
+			}
+			for i := range pk.Moves {
+				pk.Moves[i].Target = move(pk.Moves[i].Target)
+			}
+		}
+		for _, prop := range f.Props {
+			c := prop.CenterPoint()
+			pt := move(SkatePoint{X: float32(c.X), Y: float32(c.Y)})
+			prop.X, prop.Y = prop.X+round(pt.X)-c.X, prop.Y+round(pt.Y)-c.Y
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package hg
+
+import (
+	"fmt"
+
+	"github.com/ebitengine/debugui"
+)
+
+// rinkControls picks the rink the drill is laid out on and how much of it
+// shows, with the size of a custom rink, and sets up snapping to it.
+func (g *Game) rinkControls(ctx *debugui.Context) {
+	ctx.Header("Rink", false, func() {
+		l := g.rinkLayout
+		ctx.Text(fmt.Sprintf("%s rink, %s ice", l.Size, l.Area))
+		ctx.SetGridLayout([]int{-1, -1, -1}, nil)
+		for _, size := range RinkSizes {
+			label := size.String()
+			if size == l.Size {
+				label = "[" + label + "]"
+			}
+			ctx.IDScope("size"+size.String(), func() {
+				ctx.Button(label).On(func() {
+					next := RinkLayout{Size: size, Area: l.Area}
+					if size == RinkCustom {
+						// A custom rink starts as a copy of the one in use.
+						custom := *activeRink.Rink
+						next.Custom = &custom
+					}
+					g.ChangeRink("Rink size", next)
+				})
+			})
+		}
+		ctx.SetGridLayout([]int{-1, -1, -1, -1}, nil)
+		for _, area := range RinkAreas {
+			label := area.String()
+			if area == l.Area {
+				label = "[" + label + "]"
+			}
+			ctx.IDScope("area"+area.String(), func() {
+				ctx.Button(label).On(func() {
+					next := l.clone()
+					next.Area = area
+					g.ChangeRink("Rink area", next)
+				})
+			})
+		}
+		if l.Size == RinkCustom && l.Custom != nil {
+			length, width := float64(l.Custom.Length), float64(l.Custom.Width)
+			ctx.SetGridLayout([]int{-1, -1, -1, -1}, nil)
+			ctx.Text("Length m")
+			ctx.NumberFieldF(&length, 0.5, 1).On(func() {
+				next := l.clone()
+				next.Custom.Length = float32(length)
+				g.ChangeRink("Rink size", next)
+			})
+			ctx.Text("Width m")
+			ctx.NumberFieldF(&width, 0.5, 1).On(func() {
+				next := l.clone()
+				next.Custom.Width = float32(width)
+				g.ChangeRink("Rink size", next)
+			})
+		}
+		ctx.SetGridLayout([]int{-1, -1, -1}, nil)
+		ctx.Checkbox(&g.snapper.Landmarks, "Snap")
+		ctx.Checkbox(&g.snapper.Grid, "Grid m")
+		ctx.NumberFieldF(&g.snapper.GridMetres, 0.5, 1)
+		g.snapper.GridMetres = max(g.snapper.GridMetres, 0.5)
+		ctx.SetGridLayout(nil, nil)
+		ctx.Text("Hold Alt to place without snapping")
+	})
+}
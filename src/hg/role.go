@@ -0,0 +1,67 @@
+package hg
+
+import (
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+)
+
+// Role is what part a player plays in a drill, which sets the shape of
+// their sprite.
+type Role string
+
+const (
+	// RoleSkater is a round sprite.
+	RoleSkater Role = ""
+	// RoleGoalie is a square sprite, and may track the puck, see GoalieTracking.
+	RoleGoalie Role = "goalie"
+	// RoleCoach is a triangle.
+	RoleCoach Role = "coach"
+	// RoleMarker is a diamond marking a spot or a generic player, drawn
+	// without facing marks.
+	RoleMarker Role = "marker"
+)
+
+// Roles lists every role.
+var Roles = []Role{RoleSkater, RoleGoalie, RoleCoach, RoleMarker}
+
+func (r Role) String() string {
+	if r == RoleSkater {
+		return "skater"
+	}
+	return string(r)
+}
+
+// roleOutline is the polygon of a sprite of radius r centred on centre, or
+// nil for the circle of a skater.
+func roleOutline(role Role, centre SkatePoint, r float32) []SkatePoint {
+	var corners []SkatePoint
+	switch role {
+	case RoleGoalie:
+		s := r * 0.85
+		corners = []SkatePoint{{X: -s, Y: -s}, {X: s, Y: -s}, {X: s, Y: s}, {X: -s, Y: s}}
+	case RoleCoach:
+		corners = []SkatePoint{{X: 0, Y: -r}, {X: r * 0.95, Y: r * 0.7}, {X: -r * 0.95, Y: r * 0.7}}
+	case RoleMarker:
+		corners = []SkatePoint{{X: 0, Y: -r}, {X: r, Y: 0}, {X: 0, Y: r}, {X: -r, Y: 0}}
+	default:
+		return nil
+	}
+	for i := range corners {
+		corners[i] = corners[i].Add(centre)
+	}
+	return corners
+}
+
+// MakeSprite draws a player sprite of radius r in the shape of role, see
+// MakeCircle.
+func MakeSprite(role Role, letters string, r float32, clr color.RGBA) (*ebiten.Image, error) {
+	outline := roleOutline(role, SkatePoint{X: r, Y: r}, r)
+	if outline == nil {
+		return MakeCircle(letters, r, clr)
+	}
+	img := ebiten.NewImage(int(r*2), int(r*2))
+	clr.A = 0xff
+	fillPolygon(img, outline, clr)
+	return img, drawSpriteText(img, letters, r)
+}
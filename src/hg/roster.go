@@ -0,0 +1,155 @@
+package hg
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DefaultRosterPath is the roster new drills are drawn from, if the file exists.
+const DefaultRosterPath = "roster.json"
+
+// Roster is the teams in a drill, in the order of Player.Team.
+//
+//	{"Teams": [
+//	 {"Name": "Home", "Color": "#800000",
+//	  "Players": [{"Symbol": "C", "Number": 17, "Name": "Sam", "Role": ""}, {"Symbol": "G", "Number": 0, "Name": "", "Role": "goalie"}]}
+//	]}
+//
+// Each team's Players are its row of the palette.  The palette shows the
+// first two teams and the first eleven players of each.
+type Roster struct {
+	Teams []Team
+}
+
+// Team is one side of a drill.
+type Team struct {
+	Name    string
+	Color   TeamColor
+	Players []RosterPlayer
+}
+
+// RosterPlayer is a player on a team's palette.  A Number is worn on their
+// sprite in place of their Symbol.
+type RosterPlayer struct {
+	Symbol string
+	// Number is their jersey number, 0 for none.
+	Number int
+	Name   string
+	Role   Role
+}
+
+// TeamColor is a sprite color, written "#rrggbb".
+type TeamColor color.RGBA
+
+// MarshalText writes c as "#rrggbb".
+func (c TeamColor) MarshalText() ([]byte, error) {
+	return []byte(svgColor(color.RGBA(c))), nil
+}
+
+// UnmarshalText reads "#rrggbb".
+func (c *TeamColor) UnmarshalText(text []byte) error {
+	s := string(text)
+	v, err := strconv.ParseUint(strings.TrimPrefix(s, "#"), 16, 32)
+	if err != nil || len(s) != 7 || s[0] != '#' {
+		return fmt.Errorf("color %q is not #rrggbb", s)
+	}
+	*c = TeamColor{uint8(v >> 16), uint8(v >> 8), uint8(v), 0xff}
+	return nil
+}
+
+// unknownTeamColor is the sprite color of players whose team is not in the roster.
+var unknownTeamColor = color.RGBA{0x60, 0x60, 0x60, 0xff}
+
+// DefaultRoster is the two teams of positions used when there is no roster file.
+func DefaultRoster() *Roster {
+	r := &Roster{Teams: []Team{
+		{Name: "Red", Color: TeamColor{0x80, 0, 0, 0xff}},
+		{Name: "Blue", Color: TeamColor{0, 0, 0xf0, 0xff}},
+	}}
+	for i := range r.Teams {
+		for _, symbol := range strings.Split("LW,RW,C,F,F1,F2,LD,RD,D", ",") {
+			r.Teams[i].Players = append(r.Teams[i].Players, RosterPlayer{Symbol: symbol})
+		}
+		r.Teams[i].Players = append(r.Teams[i].Players,
+			RosterPlayer{Symbol: "G", Role: RoleGoalie},
+			RosterPlayer{Symbol: "X", Role: RoleMarker})
+	}
+	return r
+}
+
+// ParseRoster decodes and validates a roster.
+func ParseRoster(data []byte) (*Roster, error) {
+	r := &Roster{}
+	if err := decodeStrict(data, r); err != nil {
+		return nil, fmt.Errorf("roster: %w", err)
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// LoadRosterFile reads and parses the roster at path.
+func LoadRosterFile(path string) (*Roster, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	r, err := ParseRoster(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return r, nil
+}
+
+// Validate reports every problem found in the roster, or nil if there are none.
+// Teams and players are numbered from 1.
+func (r *Roster) Validate() error {
+	var errs []error
+	if len(r.Teams) == 0 {
+		errs = append(errs, errors.New("roster has no teams"))
+	}
+	for ti, team := range r.Teams {
+		for pi, p := range team.Players {
+			if p.Symbol == "" && p.Number == 0 {
+				errs = append(errs, fmt.Errorf("roster: team %d (%q) player %d has no Symbol or Number", ti+1, team.Name, pi+1))
+			}
+			if !slices.Contains(Roles, p.Role) {
+				errs = append(errs, fmt.Errorf("roster: team %d (%q) player %d has unknown Role %q", ti+1, team.Name, pi+1, p.Role))
+			}
+			if p.Number < 0 {
+				errs = append(errs, fmt.Errorf("roster: team %d (%q) player %d has negative Number %d", ti+1, team.Name, pi+1, p.Number))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// teamColor is the sprite color of team.
+func (r *Roster) teamColor(team int) color.RGBA {
+	if team < 0 || team >= len(r.Teams) {
+		return unknownTeamColor
+	}
+	return color.RGBA(r.Teams[team].Color)
+}
+
+// teamName is the name of team, or its number if it has none.
+func (r *Roster) teamName(team int) string {
+	if team >= 0 && team < len(r.Teams) && r.Teams[team].Name != "" {
+		return r.Teams[team].Name
+	}
+	return fmt.Sprintf("team %d", team+1)
+}
+
+// spriteLabel is what is written on a player's sprite.
+func spriteLabel(symbol string, number int) string {
+	if number > 0 {
+		return strconv.Itoa(number)
+	}
+	return symbol
+}
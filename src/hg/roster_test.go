@@ -0,0 +1,53 @@
+package hg
+
+import (
+	"encoding/json"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoster(t *testing.T) {
+	r, err := ParseRoster([]byte(`{"Teams": [
+	 {"Name": "Home", "Color": "#1e90FF", "Players": [{"Symbol": "C", "Number": 17, "Name": "Sam"}, {"Symbol": "LW"}]},
+	 {"Name": "", "Color": "#000000", "Players": []}
+	]}`))
+	require.NoError(t, err)
+	assert.Equal(t, color.RGBA{0x1e, 0x90, 0xff, 0xff}, r.teamColor(0))
+	assert.Equal(t, unknownTeamColor, r.teamColor(2))
+	assert.Equal(t, "Home", r.teamName(0))
+	assert.Equal(t, "team 2", r.teamName(1))
+	assert.Equal(t, "17", spriteLabel(r.Teams[0].Players[0].Symbol, r.Teams[0].Players[0].Number))
+	assert.Equal(t, "LW", spriteLabel(r.Teams[0].Players[1].Symbol, r.Teams[0].Players[1].Number))
+
+	data, err := json.Marshal(r)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"Color":"#1e90ff"`)
+
+	_, err = ParseRoster([]byte(`{"Teams": [{"Name": "Home", "Color": "red"}]}`))
+	assert.ErrorContains(t, err, `color "red" is not #rrggbb`)
+	_, err = ParseRoster([]byte(`{"Teams": [{"Name": "Home", "Color": "#ffffff", "Players": [{}, {"Symbol": "D", "Number": -4}]}]}`))
+	assert.ErrorContains(t, err, `roster: team 1 ("Home") player 1 has no Symbol or Number`)
+	assert.ErrorContains(t, err, `roster: team 1 ("Home") player 2 has negative Number -4`)
+	_, err = ParseRoster([]byte(`{"Teams": []}`))
+	assert.ErrorContains(t, err, "roster has no teams")
+}
+
+func TestDrillRoster(t *testing.T) {
+	// Drills from before rosters have the default two teams.
+	d, err := ParseDrill([]byte(legacyDrill))
+	require.NoError(t, err)
+	assert.Empty(t, d.Roster.Teams)
+	assert.Equal(t, DefaultRoster(), d.roster())
+
+	d.Roster = Roster{Teams: []Team{{Name: "Solo", Color: TeamColor{0, 0x80, 0, 0xff}}}}
+	assert.ErrorContains(t, d.Validate(), `frame 1: player 2 ("LD") has Team 1 but the Roster has 1 teams`)
+	d.Frames[0].Players.Players[1].Team = 0
+	data, err := d.Marshal()
+	require.NoError(t, err)
+	again, err := ParseDrill(data)
+	require.NoError(t, err)
+	assert.Equal(t, d.Roster, again.Roster)
+}
@@ -0,0 +1,91 @@
+package hg
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// shape is a filled or outlined polygon, drawn the same way on screen and in
+// SVG exports.  It is filled if fill is not transparent and outlined if
+// width is not zero.
+type shape struct {
+	points []SkatePoint
+	closed bool
+	fill   color.RGBA
+	line   color.RGBA
+	width  float32
+}
+
+// strokePoints are the points outlined, closing the polygon if need be.
+func (s shape) strokePoints() []SkatePoint {
+	if s.closed {
+		return append(s.points[:len(s.points):len(s.points)], s.points[0])
+	}
+	return s.points
+}
+
+// drawShapes draws shapes on dst in order.
+func drawShapes(dst *ebiten.Image, shapes []shape) {
+	for _, s := range shapes {
+		if s.fill.A > 0 {
+			fillPolygon(dst, s.points, s.fill)
+		}
+		if s.width > 0 {
+			drawStrokes(dst, []stroke{{s.strokePoints(), s.width}}, s.line)
+		}
+	}
+}
+
+// writeSVGShapes writes shapes as SVG polygons and polylines.
+func writeSVGShapes(w io.Writer, shapes []shape) {
+	for _, s := range shapes {
+		if s.fill.A > 0 {
+			fmt.Fprintf(w, `<polygon fill="%s" fill-opacity="%.2f" points="%s"/>`,
+				svgColor(s.fill), float32(s.fill.A)/0xff, svgPoints(s.points))
+		}
+		if s.width > 0 {
+			fmt.Fprintf(w, `<polyline fill="none" stroke="%s" stroke-width="%g" stroke-linejoin="round" stroke-linecap="round" points="%s"/>`,
+				svgColor(s.line), s.width, svgPoints(s.strokePoints()))
+		}
+	}
+}
+
+// circlePoints approximates a circle as a polygon.
+func circlePoints(centre SkatePoint, r float32) []SkatePoint {
+	const n = 24
+	points := make([]SkatePoint, n)
+	for i := range points {
+		a := 2 * math.Pi * float64(i) / n
+		points[i] = centre.Add(SkatePoint{X: float32(math.Cos(a)), Y: float32(math.Sin(a))}.Mul(r))
+	}
+	return points
+}
+
+// fillPolygon fills the polygon through points in col.
+func fillPolygon(dst *ebiten.Image, points []SkatePoint, col color.RGBA) {
+	path := vector.Path{}
+	path.MoveTo(points[0].X, points[0].Y)
+	for _, p := range points[1:] {
+		path.LineTo(p.X, p.Y)
+	}
+	path.Close()
+	vertices, indices := path.AppendVerticesAndIndicesForFilling(nil, nil)
+	for i := range vertices {
+		vertices[i].SrcX = 1
+		vertices[i].SrcY = 1
+		vertices[i].ColorR = float32(col.R) / 0xff
+		vertices[i].ColorG = float32(col.G) / 0xff
+		vertices[i].ColorB = float32(col.B) / 0xff
+		vertices[i].ColorA = float32(col.A) / 0xff
+	}
+	dst.DrawTriangles(vertices, indices, whiteSubImage, &ebiten.DrawTrianglesOptions{
+		AntiAlias:      true,
+		FillRule:       ebiten.FillRuleNonZero,
+		ColorScaleMode: ebiten.ColorScaleModeStraightAlpha,
+	})
+}
@@ -0,0 +1,160 @@
+package hg
+
+import "math"
+
+// SimplifyTolerance is how far in pixels a simplified stroke may stray from
+// what was drawn.
+const SimplifyTolerance = 6
+
+// cornerReach is how far along the stroke either side of a corner is looked
+// at to see how tightly it turns.
+const cornerReach = 25
+
+// SimplifyStroke turns a dense freehand stroke into a SkatePathWithRadius
+// with few control points.  Ramer-Douglas-Peucker picks the corners from the
+// stroke's own points, so the path still passes through them, and each
+// corner's radius is fitted to how tightly the stroke turns there.  RDP
+// needs several points to follow a curve, so runs of them are then
+// collapsed into single rounded corners wherever that stays within
+// tolerance.
+func SimplifyStroke(points []SkatePoint, tolerance float32) *SkatePathWithRadius {
+	if len(points) == 0 {
+		return &SkatePathWithRadius{editPointIndex: -1, editRadiusIndex: -1}
+	}
+	keep := simplifyIndices(points, tolerance)
+	for n := 1; n < len(keep)-1; n++ {
+		for m := len(keep) - 2; m >= n; m-- {
+			if strokeLength(points[keep[n]:keep[m]+1]) > cornerSpan {
+				continue
+			}
+			if collapsed, ok := collapseCorner(points, keep, n, m, tolerance); ok {
+				keep = collapsed
+				break
+			}
+		}
+	}
+	return roundedPath(points, keep)
+}
+
+// cornerSpan is the longest run of corners collapseCorner tries to turn
+// into one.
+const cornerSpan = 8 * cornerReach
+
+// collapseCorner replaces keep[n:m+1] with no corner or the single stroke
+// point furthest from the line past them, if the result is within tolerance.
+func collapseCorner(points []SkatePoint, keep []int, n, m int, tolerance float32) ([]int, bool) {
+	first, last := keep[n-1], keep[m+1]
+	stroke := points[first : last+1]
+	without := append(keep[:n:n], keep[m+1:]...)
+	if strokeDeviation(stroke, roundedPath(points, without)) <= tolerance {
+		return without, true
+	}
+	pick, pickDist := -1, float32(0)
+	for i := first + 1; i < last; i++ {
+		if d := pointToLineSegmentDist(points[i], points[first], points[last]); d > pickDist {
+			pick, pickDist = i, d
+		}
+	}
+	if pick < 0 || m == n && keep[n] == pick {
+		return nil, false
+	}
+	one := append(append(keep[:n:n], pick), keep[m+1:]...)
+	if strokeDeviation(stroke, roundedPath(points, one)) <= tolerance {
+		return one, true
+	}
+	return nil, false
+}
+
+// strokeLength is the length of the stroke through points.
+func strokeLength(points []SkatePoint) float32 {
+	return (&SkatePath{Points: points}).TotalLength()
+}
+
+// roundedPath is the path through the stroke points at keep with fitted corners.
+func roundedPath(points []SkatePoint, keep []int) *SkatePathWithRadius {
+	sp := &SkatePathWithRadius{editPointIndex: -1, editRadiusIndex: -1}
+	for n, i := range keep {
+		radius := float32(0)
+		if n > 0 && n < len(keep)-1 {
+			radius = fitCornerRadius(points, i, points[keep[n-1]], points[keep[n+1]])
+		}
+		sp.Points = append(sp.Points, points[i])
+		sp.PointRadiuses = append(sp.PointRadiuses, radius)
+	}
+	return sp
+}
+
+// strokeDeviation is the furthest any of stroke is from sp.
+func strokeDeviation(stroke []SkatePoint, sp *SkatePathWithRadius) float32 {
+	path := sp.pathPoints()
+	var worst float32
+	for _, p := range stroke {
+		best := float32(math.MaxFloat32)
+		for i := 0; i < len(path)-1; i++ {
+			best = min(best, pointToLineSegmentDistSquared(p, path[i], path[i+1]))
+		}
+		worst = max(worst, best)
+	}
+	return float32(math.Sqrt(float64(worst)))
+}
+
+// simplifyIndices returns the indices of the points Ramer-Douglas-Peucker keeps.
+func simplifyIndices(points []SkatePoint, tolerance float32) []int {
+	keep := []int{0}
+	var split func(first, last int)
+	split = func(first, last int) {
+		worst, worstDist := -1, tolerance
+		for i := first + 1; i < last; i++ {
+			if d := pointToLineSegmentDist(points[i], points[first], points[last]); d > worstDist {
+				worst, worstDist = i, d
+			}
+		}
+		if worst < 0 {
+			return
+		}
+		split(first, worst)
+		keep = append(keep, worst)
+		split(worst, last)
+	}
+	last := len(points) - 1
+	if last > 0 {
+		split(0, last)
+		keep = append(keep, last)
+	}
+	return keep
+}
+
+// fitCornerRadius estimates the radius of the stroke at points[i] from the
+// circle through it and the stroke cornerReach either side.  It is limited
+// to what SkatePathWithRadius can draw between prev and next.
+func fitCornerRadius(points []SkatePoint, i int, prev, next SkatePoint) float32 {
+	v := points[i]
+	limit := min(v.Sub(prev).Length(), v.Sub(next).Length()) / 2
+	reach := min(float32(cornerReach), limit)
+	a, b := strokeAt(points, i, -1, reach), strokeAt(points, i, 1, reach)
+
+	va, vb, ab := a.Sub(v), b.Sub(v), b.Sub(a)
+	cross := float32(math.Abs(float64(va.X*vb.Y - va.Y*vb.X)))
+	if cross < 1e-3 {
+		return limit
+	}
+	radius := va.Length() * vb.Length() * ab.Length() / (2 * cross)
+	return min(radius, limit)
+}
+
+// strokeAt walks from points[i] in direction dir until it has covered
+// distance, or runs out of stroke.
+func strokeAt(points []SkatePoint, i, dir int, distance float32) SkatePoint {
+	var covered float32
+	for j := i + dir; j >= 0 && j < len(points); j += dir {
+		step := points[j].Sub(points[j-dir]).Length()
+		if covered+step >= distance && step > 0 {
+			return points[j-dir].Add(points[j].Sub(points[j-dir]).Mul((distance - covered) / step))
+		}
+		covered += step
+	}
+	if dir < 0 {
+		return points[0]
+	}
+	return points[len(points)-1]
+}
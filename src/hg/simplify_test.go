@@ -0,0 +1,42 @@
+package hg
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimplifyStroke(t *testing.T) {
+	// Along, round a corner of radius 40, then down, every 2 pixels.
+	var stroke []SkatePoint
+	for x := float32(0); x < 200; x += 2 {
+		stroke = append(stroke, SkatePoint{X: x, Y: 0})
+	}
+	for a := 0.0; a < math.Pi/2; a += 0.05 {
+		stroke = append(stroke, SkatePoint{X: 200 + 40*float32(math.Sin(a)), Y: 40 - 40*float32(math.Cos(a))})
+	}
+	for y := float32(40); y <= 240; y += 2 {
+		stroke = append(stroke, SkatePoint{X: 240, Y: y})
+	}
+
+	sp := SimplifyStroke(stroke, SimplifyTolerance)
+	require.Len(t, sp.Points, 3)
+	assert.Equal(t, stroke[0], sp.Points[0])
+	assert.Equal(t, stroke[len(stroke)-1], sp.Points[2])
+	assert.Equal(t, float32(0), sp.PointRadiuses[0])
+	assert.InDelta(t, 40, sp.PointRadiuses[1], 10)
+
+	// Every point of the rounded path is close to the stroke.
+	for _, p := range sp.pathPoints() {
+		best := float32(math.MaxFloat32)
+		for i := 0; i < len(stroke)-1; i++ {
+			best = min(best, pointToLineSegmentDist(p, stroke[i], stroke[i+1]))
+		}
+		assert.Less(t, best, float32(3*SimplifyTolerance))
+	}
+
+	line := SimplifyStroke([]SkatePoint{{X: 0, Y: 0}, {X: 5, Y: 1}, {X: 10, Y: 0}}, SimplifyTolerance)
+	assert.Equal(t, []SkatePoint{{X: 0, Y: 0}, {X: 10, Y: 0}}, line.Points)
+}
@@ -1,6 +1,7 @@
 package hg
 
 import (
+	"fmt"
 	"image"
 	"math"
 	"slices"
@@ -57,11 +58,38 @@ func (p SkatePoint) Length() float32 {
 	return float32(math.Sqrt(float64(p.LengthSq())))
 }
 
+// SkatePath is a freehand path, kept as the points it was drawn with.
 type SkatePath struct {
-	TargetId int
-	Points   []SkatePoint
+	Points []SkatePoint
+	Facing
 }
 
+// Kind implements Path.
+func (sp *SkatePath) Kind() string { return "freehand" }
+
+// Polyline implements Path.
+func (sp *SkatePath) Polyline() []SkatePoint { return sp.Points }
+
+// Heading implements Path.
+func (sp *SkatePath) Heading(fraction float32) float32 { return headingAlong(sp.Points, fraction) }
+
+// Bounds implements Path.
+func (sp *SkatePath) Bounds() image.Rectangle { return polylineBounds(sp.Points) }
+
+// ClonePath implements Path.
+func (sp *SkatePath) ClonePath() Path { return sp.Clone() }
+
+// Transform implements Path.
+func (sp *SkatePath) Transform(scale float32, offset SkatePoint) {
+	transformPoints(sp.Points, scale, offset)
+}
+
+// Segments implements Path, a freehand path is a single segment.
+func (sp *SkatePath) Segments() []float32 { return []float32{0, 1} }
+
+// Validate implements Path, any points can be drawn freehand.
+func (sp *SkatePath) Validate() error { return sp.validateFacing(1) }
+
 func (sp *SkatePath) Draw(screen *ebiten.Image) {
 	sp.drawActive(screen, nil)
 }
@@ -71,6 +99,9 @@ func (sp *SkatePath) DrawActive(screen *ebiten.Image, lastPoint image.Point) {
 }
 
 func (sp *SkatePath) drawActive(screen *ebiten.Image, lastPoint *image.Point) {
+	if len(sp.Points) == 0 {
+		return
+	}
 	path := vector.Path{}
 	pt := sp.Points[0]
 	path.MoveTo(float32(pt.X), float32(pt.Y))
@@ -217,15 +248,96 @@ func (sp *SkatePath) TruncatePathToFraction(fraction float32) {
 	}
 }
 
+// Clone returns a deep copy of the path.
+func (sp *SkatePath) Clone() *SkatePath {
+	if sp == nil {
+		return nil
+	}
+	c := *sp
+	c.Points = slices.Clone(sp.Points)
+	c.Backwards = slices.Clone(sp.Backwards)
+	return &c
+}
+
+// SkatePathWithRadius is a path through control Points with a rounded corner
+// of PointRadiuses at each.
 type SkatePathWithRadius struct {
-	TargetId      int
 	Points        []SkatePoint
 	PointRadiuses []float32
+	Facing
 	// Todo - break out to SkatePathWithRadiusEditor struct
 	editPointIndex  int
 	editRadiusIndex int
 }
 
+// Clone returns a deep copy of the path that is not being edited.
+func (sp *SkatePathWithRadius) Clone() *SkatePathWithRadius {
+	if sp == nil {
+		return nil
+	}
+	c := *sp
+	c.Points = slices.Clone(sp.Points)
+	c.PointRadiuses = slices.Clone(sp.PointRadiuses)
+	c.Backwards = slices.Clone(sp.Backwards)
+	c.editPointIndex = -1
+	c.editRadiusIndex = -1
+	return &c
+}
+
+// Kind implements Path.
+func (sp *SkatePathWithRadius) Kind() string { return "radius" }
+
+// Polyline implements Path.
+func (sp *SkatePathWithRadius) Polyline() []SkatePoint { return sp.pathPoints() }
+
+// Heading implements Path.
+func (sp *SkatePathWithRadius) Heading(fraction float32) float32 {
+	return headingAlong(sp.pathPoints(), fraction)
+}
+
+// Bounds implements Path.
+func (sp *SkatePathWithRadius) Bounds() image.Rectangle { return polylineBounds(sp.pathPoints()) }
+
+// ClonePath implements Path.
+func (sp *SkatePathWithRadius) ClonePath() Path { return sp.Clone() }
+
+// Transform implements Path.
+func (sp *SkatePathWithRadius) Transform(scale float32, offset SkatePoint) {
+	transformPoints(sp.Points, scale, offset)
+	for i := range sp.PointRadiuses {
+		sp.PointRadiuses[i] *= scale
+	}
+}
+
+// Validate implements Path.
+func (sp *SkatePathWithRadius) Validate() error {
+	if len(sp.PointRadiuses) != len(sp.Points) {
+		return fmt.Errorf("has %d radius path points but %d PointRadiuses", len(sp.Points), len(sp.PointRadiuses))
+	}
+	return sp.validateFacing(max(0, len(sp.Points)-1))
+}
+
+// Segments implements Path.
+func (sp *SkatePathWithRadius) Segments() []float32 {
+	return controlFractions(sp.pathPoints(), sp.Points)
+}
+
+// UnmarshalJSON decodes a path that is not being edited.
+func (sp *SkatePathWithRadius) UnmarshalJSON(data []byte) error {
+	type plain SkatePathWithRadius
+	p := plain{editPointIndex: -1, editRadiusIndex: -1}
+	if err := decodeStrict(data, &p); err != nil {
+		return err
+	}
+	*sp = SkatePathWithRadius(p)
+	return nil
+}
+
+// Editing reports whether a point or radius is being dragged.
+func (sp *SkatePathWithRadius) Editing() bool {
+	return sp.editPointIndex > -1 || sp.editRadiusIndex > -1
+}
+
 func (sp *SkatePathWithRadius) DistancePathToPoint(p SkatePoint) float32 {
 	dist := float32(100000)
 	end := len(sp.Points) - 1
@@ -236,11 +348,12 @@ func (sp *SkatePathWithRadius) DistancePathToPoint(p SkatePoint) float32 {
 	return float32(math.Sqrt(float64(dist)))
 }
 
-func (sp *SkatePathWithRadius) UpdateForEdit(mouseController *MouseController) {
+func (sp *SkatePathWithRadius) UpdateForEdit(mouseController *MouseController, snapper *Snapper) {
 	const selectRadius = 10
 	const selectRadius2 = selectRadius * selectRadius
 	mp := SkatePoint{}
-	if mouseController.DragStart() {
+	grabbing := mouseController.DragStart()
+	if grabbing {
 		mx, my := mouseController.Position()
 		mp = SkatePoint{X: float32(mx), Y: float32(my)}
 	}
@@ -251,7 +364,7 @@ func (sp *SkatePathWithRadius) UpdateForEdit(mouseController *MouseController) {
 	if sp.editPointIndex > -1 {
 		mx, my := mouseController.Position()
 		mp = SkatePoint{X: float32(mx), Y: float32(my)}
-		sp.Points[sp.editPointIndex] = mp
+		sp.Points[sp.editPointIndex] = snapper.Snap(mp)
 	}
 	if sp.editRadiusIndex > -1 {
 		mx, my := mouseController.Position()
@@ -268,9 +381,14 @@ func (sp *SkatePathWithRadius) UpdateForEdit(mouseController *MouseController) {
 		sp.PointRadiuses[i] = p.Sub(mp).Length()
 
 	}
+	if !grabbing {
+		// Only pick up a handle as a drag starts.
+		return
+	}
 	insertPointIndex := -1
 	for i, p := range sp.Points {
-		if p.Sub(mp).LengthSq() < selectRadius2 {
+		// The first point sits under the player, who is dragged instead.
+		if i > 0 && p.Sub(mp).LengthSq() < selectRadius2 {
 			sp.editPointIndex = i
 		}
 		if i < len(sp.Points)-1 {
@@ -295,8 +413,9 @@ func (sp *SkatePathWithRadius) UpdateForEdit(mouseController *MouseController) {
 	}
 	if insertPointIndex >= 0 {
 		i := insertPointIndex + 1
-		sp.Points = slices.Insert(sp.Points, i, mp)
+		sp.Points = slices.Insert(sp.Points, i, snapper.Snap(mp))
 		sp.PointRadiuses = slices.Insert(sp.PointRadiuses, i, 5)
+		sp.splitSegment(i)
 		sp.editPointIndex = insertPointIndex + 1
 	}
 }
@@ -326,6 +445,9 @@ func (sp *SkatePathWithRadius) DrawForEdit(screen *ebiten.Image) {
 func (sp *SkatePathWithRadius) Draw(screen *ebiten.Image) {
 	path := vector.Path{}
 	points := sp.pathPoints()
+	if len(points) < 2 {
+		return
+	}
 	path.MoveTo(float32(points[0].X), float32(points[0].Y))
 	for _, p := range points[1:] {
 		path.LineTo(float32(p.X), float32(p.Y))
@@ -334,6 +456,17 @@ func (sp *SkatePathWithRadius) Draw(screen *ebiten.Image) {
 	dispatchPath(screen, &path, 3)
 }
 
+// TotalLength is the length of the path including its rounded corners.
+func (sp *SkatePathWithRadius) TotalLength() float32 {
+	return (&SkatePath{Points: sp.pathPoints()}).TotalLength()
+}
+
+// Interpolate returns the point fraction of the way along the path by arc
+// length, following its rounded corners.
+func (sp *SkatePathWithRadius) Interpolate(fraction float32) SkatePoint {
+	return (&SkatePath{Points: sp.pathPoints()}).Interpolate(fraction)
+}
+
 func (sp *SkatePathWithRadius) pathPoints() []SkatePoint {
 	if len(sp.Points) < 2 {
 		return nil
@@ -392,6 +525,8 @@ func (sp *SkatePathWithRadius) pathPoints() []SkatePoint {
 
 			result = append(result, exitPoint)
 			//path.LineTo(exitPoint.X, exitPoint.Y)
+		} else {
+			result = append(result, pt)
 		}
 	}
 	return result
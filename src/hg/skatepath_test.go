@@ -0,0 +1,29 @@
+package hg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSkatePathWithRadiusInterpolate(t *testing.T) {
+	sp := &SkatePathWithRadius{
+		Points:        []SkatePoint{{X: 0, Y: 0}, {X: 100, Y: 0}, {X: 100, Y: 100}},
+		PointRadiuses: []float32{0, 0, 0},
+	}
+	assert.InDelta(t, 200, sp.TotalLength(), 0.01)
+	assert.Equal(t, SkatePoint{X: 100, Y: 50}, sp.Interpolate(0.75))
+
+	// A rounded corner still passes through its point and ends in the same place.
+	sp.PointRadiuses[1] = 20
+	assert.Greater(t, sp.TotalLength(), float32(200))
+	assert.Equal(t, SkatePoint{X: 0, Y: 0}, sp.Interpolate(0))
+	assert.Equal(t, SkatePoint{X: 100, Y: 100}, sp.Interpolate(1))
+	closest := float32(1000)
+	for _, p := range sp.pathPoints() {
+		closest = min(closest, p.Sub(SkatePoint{X: 100, Y: 0}).Length())
+	}
+	assert.Less(t, closest, float32(0.01))
+
+	assert.Equal(t, SkatePoint{}, (&SkatePathWithRadius{}).Interpolate(0.5))
+}
@@ -0,0 +1,175 @@
+package hg
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// snapRadius is how close in pixels a point must come to a landmark or line
+// to snap to it.
+const snapRadius = 12
+
+// Snapper pulls points being placed onto nearby rink landmarks and, when
+// Grid is on, onto a grid.  Holding Alt places points freely.
+type Snapper struct {
+	Landmarks bool
+	Grid      bool
+	// GridMetres is the grid spacing on the ice, measured from centre ice.
+	GridMetres float64
+	// guide is what the last point snapped to, for drawing.
+	guide snapGuide
+}
+
+// snapKind is what a point snapped to.
+type snapKind int
+
+const (
+	snapNone snapKind = iota
+	snapLandmark
+	// snapLine is a line across the rink, which only sets X.
+	snapLine
+	snapGrid
+)
+
+// snapGuide is where a point snapped to.
+type snapGuide struct {
+	kind snapKind
+	at   SkatePoint
+}
+
+var (
+	snapColor = color.RGBA{0x20, 0xa0, 0x20, 0xff}
+	gridColor = color.RGBA{0xb0, 0xb0, 0xb0, 0xff}
+)
+
+// landmarks are the points on the rink things snap to, in pixels: the
+// faceoff dots, the tops and bottoms of the end circles, where their hash
+// marks meet them and the middles of the goal mouths.
+func (v *RinkView) landmarks() []SkatePoint {
+	r := v.Rink
+	points := r.FaceoffDots()
+	for _, c := range r.EndCircles() {
+		points = append(points, c.Add(SkatePoint{Y: -r.FaceoffRadius}), c.Add(SkatePoint{Y: r.FaceoffRadius}))
+		for _, mark := range r.hashMarks(c) {
+			points = append(points, mark[0])
+		}
+	}
+	nets := r.NetCentres()
+	points = append(points, nets[0], nets[1])
+	for i, p := range points {
+		points[i] = v.ToPixels(p)
+	}
+	return points
+}
+
+// snapLines are the x in pixels of the lines across the rink things snap
+// to: the goal lines, the blue lines and the centre line.
+func (v *RinkView) snapLines() []float32 {
+	r := v.Rink
+	goals, blues := r.GoalLines(), r.BlueLines()
+	var lines []float32
+	for _, x := range []float32{goals[0], blues[0], r.Length / 2, blues[1], goals[1]} {
+		lines = append(lines, v.ToPixels(SkatePoint{X: x}).X)
+	}
+	return lines
+}
+
+// Snap moves pt onto what it snaps to on the active rink, unless s is nil
+// or Alt is held.
+func (s *Snapper) Snap(pt SkatePoint) SkatePoint {
+	if s == nil {
+		return pt
+	}
+	s.guide = snapGuide{}
+	if ebiten.IsKeyPressed(ebiten.KeyAlt) {
+		return pt
+	}
+	pt, s.guide = s.snap(activeRink, pt)
+	return pt
+}
+
+// snap moves pt on v to the nearest landmark within snapRadius, or else
+// onto a nearby line and the grid.  Points off the ice are left alone.
+func (s *Snapper) snap(v *RinkView, pt SkatePoint) (SkatePoint, snapGuide) {
+	if !v.OnIce(int(pt.X), int(pt.Y)) {
+		return pt, snapGuide{}
+	}
+	guide := snapGuide{}
+	if s.Landmarks {
+		nearest := float32(snapRadius * snapRadius)
+		for _, l := range v.landmarks() {
+			if d := l.Sub(pt).LengthSq(); d < nearest {
+				nearest, guide = d, snapGuide{kind: snapLandmark, at: l}
+			}
+		}
+		if guide.kind == snapLandmark {
+			return guide.at, guide
+		}
+		for _, x := range v.snapLines() {
+			if float32(math.Abs(float64(pt.X-x))) < snapRadius {
+				pt.X, guide.kind = x, snapLine
+				break
+			}
+		}
+	}
+	if s.Grid && s.GridMetres > 0 {
+		step := float32(s.GridMetres) * v.Scale
+		centre := v.ToPixels(v.Rink.Centre())
+		round := func(p, origin float32) float32 {
+			return origin + step*float32(math.Round(float64((p-origin)/step)))
+		}
+		if guide.kind != snapLine {
+			pt.X, guide.kind = round(pt.X, centre.X), snapGrid
+		}
+		pt.Y = round(pt.Y, centre.Y)
+	}
+	guide.at = pt
+	return pt, guide
+}
+
+// clearGuide forgets what the last point snapped to.
+func (s *Snapper) clearGuide() {
+	s.guide = snapGuide{}
+}
+
+// drawGrid draws the grid's points on the ice, when it is on.
+func (s *Snapper) drawGrid(screen *ebiten.Image) {
+	if !s.Grid || s.GridMetres <= 0 {
+		return
+	}
+	v := activeRink
+	step := float32(s.GridMetres) * v.Scale
+	if step < 4 {
+		return
+	}
+	centre := v.ToPixels(v.Rink.Centre())
+	b := v.Bounds()
+	startX := centre.X - step*float32(math.Floor(float64((centre.X-float32(b.Min.X))/step)))
+	startY := centre.Y - step*float32(math.Floor(float64((centre.Y-float32(b.Min.Y))/step)))
+	for x := startX; x < float32(b.Max.X); x += step {
+		for y := startY; y < float32(b.Max.Y); y += step {
+			if v.OnIce(int(x), int(y)) {
+				vector.DrawFilledRect(screen, x-1, y-1, 2, 2, gridColor, false)
+			}
+		}
+	}
+}
+
+// drawGuide marks what the point being dragged snapped to: a ring round a
+// landmark, the line across the rink or a cross on the grid.
+func (s *Snapper) drawGuide(screen *ebiten.Image) {
+	g := s.guide
+	switch g.kind {
+	case snapLandmark:
+		vector.StrokeCircle(screen, g.at.X, g.at.Y, playerRadius+4, 2, snapColor, true)
+	case snapLine:
+		b := activeRink.Bounds()
+		vector.StrokeLine(screen, g.at.X, float32(b.Min.Y), g.at.X, float32(b.Max.Y), 1, snapColor, true)
+	case snapGrid:
+		vector.StrokeLine(screen, g.at.X-6, g.at.Y, g.at.X+6, g.at.Y, 1, snapColor, true)
+		vector.StrokeLine(screen, g.at.X, g.at.Y-6, g.at.X, g.at.Y+6, 1, snapColor, true)
+	}
+}
@@ -0,0 +1,45 @@
+package hg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnap(t *testing.T) {
+	v := RinkLayout{}.View()
+	r := v.Rink
+	s := &Snapper{Landmarks: true, GridMetres: 1}
+
+	// Near a faceoff dot lands on it.
+	dot := v.ToPixels(r.EndCircles()[0])
+	pt, guide := s.snap(v, dot.Add(SkatePoint{X: 5, Y: -4}))
+	assert.Equal(t, dot, pt)
+	assert.Equal(t, snapLandmark, guide.kind)
+
+	// Near the blue line only moves across onto it.
+	blue := v.ToPixels(SkatePoint{X: r.BlueLine, Y: 5})
+	pt, guide = s.snap(v, blue.Add(SkatePoint{X: 6}))
+	assert.Equal(t, blue, pt)
+	assert.Equal(t, snapLine, guide.kind)
+
+	// Open ice is left alone without the grid, and put on it with.
+	open := v.ToPixels(SkatePoint{X: 40.3, Y: 20.4})
+	pt, guide = s.snap(v, open)
+	assert.Equal(t, open, pt)
+	assert.Equal(t, snapNone, guide.kind)
+	s.Grid = true
+	pt, guide = s.snap(v, open)
+	assert.Equal(t, snapGrid, guide.kind)
+	onIce := v.ToRink(pt).Sub(r.Centre())
+	assert.InDelta(t, 10, onIce.X, 0.01)
+	assert.InDelta(t, 7, onIce.Y, 0.01)
+
+	// Nothing snaps off the ice, and without a Snapper nothing snaps at all.
+	off := SkatePoint{X: 2, Y: 2}
+	pt, guide = s.snap(v, off)
+	assert.Equal(t, off, pt)
+	assert.Equal(t, snapNone, guide.kind)
+	var none *Snapper
+	assert.Equal(t, dot.Add(SkatePoint{X: 1}), none.Snap(dot.Add(SkatePoint{X: 1})))
+}
@@ -0,0 +1,219 @@
+package hg
+
+import (
+	"fmt"
+	"image"
+	"slices"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+// splineSteps is how many straight pieces each curve of a spline is drawn with.
+const splineSteps = 16
+
+// SplinePath is a smooth curve through Points.  Each point has a tangent
+// handle, the offset to the control point of the cubic Bezier leaving it;
+// the curve arrives along the mirror of the handle, so it never kinks.
+type SplinePath struct {
+	Points  []SkatePoint
+	Handles []SkatePoint
+	Facing
+
+	editPointIndex  int
+	editHandleIndex int
+}
+
+// NewSplineThrough returns a Catmull-Rom spline through points.
+func NewSplineThrough(points []SkatePoint) *SplinePath {
+	s := &SplinePath{Points: slices.Clone(points), editPointIndex: -1, editHandleIndex: -1}
+	s.Handles = make([]SkatePoint, len(points))
+	for i := range points {
+		prev, next := points[max(0, i-1)], points[min(len(points)-1, i+1)]
+		scale := float32(1) / 6
+		if i == 0 || i == len(points)-1 {
+			scale = float32(1) / 3
+		}
+		s.Handles[i] = next.Sub(prev).Mul(scale)
+	}
+	return s
+}
+
+// Kind implements Path.
+func (s *SplinePath) Kind() string { return "spline" }
+
+// Polyline implements Path.
+func (s *SplinePath) Polyline() []SkatePoint { return s.pathPoints() }
+
+// Heading implements Path.
+func (s *SplinePath) Heading(fraction float32) float32 { return headingAlong(s.pathPoints(), fraction) }
+
+// Bounds implements Path.
+func (s *SplinePath) Bounds() image.Rectangle { return polylineBounds(s.pathPoints()) }
+
+// ClonePath implements Path.
+func (s *SplinePath) ClonePath() Path { return s.Clone() }
+
+// Transform implements Path, the Handles are only scaled as they are
+// relative to their points.
+func (s *SplinePath) Transform(scale float32, offset SkatePoint) {
+	transformPoints(s.Points, scale, offset)
+	for i := range s.Handles {
+		s.Handles[i] = s.Handles[i].Mul(scale)
+	}
+}
+
+// Validate implements Path.
+func (s *SplinePath) Validate() error {
+	if len(s.Handles) != len(s.Points) {
+		return fmt.Errorf("has %d spline points but %d Handles", len(s.Points), len(s.Handles))
+	}
+	return s.validateFacing(max(0, len(s.Points)-1))
+}
+
+// Segments implements Path.
+func (s *SplinePath) Segments() []float32 {
+	return controlFractions(s.pathPoints(), s.Points)
+}
+
+// UnmarshalJSON decodes a spline that is not being edited.
+func (s *SplinePath) UnmarshalJSON(data []byte) error {
+	type plain SplinePath
+	p := plain{editPointIndex: -1, editHandleIndex: -1}
+	if err := decodeStrict(data, &p); err != nil {
+		return err
+	}
+	*s = SplinePath(p)
+	return nil
+}
+
+// Clone returns a deep copy of the spline that is not being edited.
+func (s *SplinePath) Clone() *SplinePath {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	c.Points = slices.Clone(s.Points)
+	c.Handles = slices.Clone(s.Handles)
+	c.Backwards = slices.Clone(s.Backwards)
+	c.editPointIndex = -1
+	c.editHandleIndex = -1
+	return &c
+}
+
+// bezier returns the point t of the way along the curve from point i to i+1.
+func (s *SplinePath) bezier(i int, t float32) SkatePoint {
+	p0, p3 := s.Points[i], s.Points[i+1]
+	p1, p2 := p0.Add(s.Handles[i]), p3.Sub(s.Handles[i+1])
+	u := 1 - t
+	return p0.Mul(u * u * u).Add(p1.Mul(3 * u * u * t)).Add(p2.Mul(3 * u * t * t)).Add(p3.Mul(t * t * t))
+}
+
+func (s *SplinePath) pathPoints() []SkatePoint {
+	if len(s.Points) < 2 {
+		return nil
+	}
+	result := []SkatePoint{s.Points[0]}
+	for i := 0; i < len(s.Points)-1; i++ {
+		for step := 1; step <= splineSteps; step++ {
+			result = append(result, s.bezier(i, float32(step)/splineSteps))
+		}
+	}
+	return result
+}
+
+// TotalLength is the length of the curve.
+func (s *SplinePath) TotalLength() float32 {
+	return (&SkatePath{Points: s.pathPoints()}).TotalLength()
+}
+
+// Interpolate returns the point fraction of the way along the curve by arc
+// length rather than by Bezier parameter, which bunches up at tight bends.
+func (s *SplinePath) Interpolate(fraction float32) SkatePoint {
+	return (&SkatePath{Points: s.pathPoints()}).Interpolate(fraction)
+}
+
+// ToRadiusPath approximates the spline with rounded corners.
+func (s *SplinePath) ToRadiusPath() *SkatePathWithRadius {
+	return SimplifyStroke(s.pathPoints(), SimplifyTolerance)
+}
+
+// Editing reports whether a point or handle is being dragged.
+func (s *SplinePath) Editing() bool {
+	return s.editPointIndex > -1 || s.editHandleIndex > -1
+}
+
+// UpdateForEdit drags points, which carry their handles with them, and
+// handles, which bend the curve either side of their point.
+func (s *SplinePath) UpdateForEdit(mouseController *MouseController, snapper *Snapper) {
+	const selectRadius2 = 10 * 10
+	mx, my := mouseController.Position()
+	mp := SkatePoint{X: float32(mx), Y: float32(my)}
+	if mouseController.Dropped() {
+		s.editPointIndex = -1
+		s.editHandleIndex = -1
+	}
+	if s.editPointIndex > -1 {
+		s.Points[s.editPointIndex] = snapper.Snap(mp)
+	}
+	if i := s.editHandleIndex; i > -1 {
+		s.Handles[i] = mp.Sub(s.Points[i])
+	}
+	if !mouseController.DragStart() {
+		return
+	}
+	for i, p := range s.Points {
+		if p.Add(s.Handles[i]).Sub(mp).LengthSq() < selectRadius2 {
+			s.editHandleIndex = i
+			return
+		}
+	}
+	// The first point sits under the player, who is dragged instead.
+	for i, p := range s.Points {
+		if i > 0 && p.Sub(mp).LengthSq() < selectRadius2 {
+			s.editPointIndex = i
+			return
+		}
+	}
+}
+
+func (s *SplinePath) Draw(screen *ebiten.Image) {
+	points := s.pathPoints()
+	if len(points) < 2 {
+		return
+	}
+	path := vector.Path{}
+	path.MoveTo(points[0].X, points[0].Y)
+	for _, p := range points[1:] {
+		path.LineTo(p.X, p.Y)
+	}
+	dispatchPath(screen, &path, 3)
+}
+
+// DrawForEdit draws the curve with its points and tangent handles.
+func (s *SplinePath) DrawForEdit(screen *ebiten.Image) {
+	const diamondRadius = 10
+	s.Draw(screen)
+	for i, p := range s.Points {
+		drawDiamond(screen, p, diamondRadius)
+		out, in := p.Add(s.Handles[i]), p.Sub(s.Handles[i])
+		handles := vector.Path{}
+		handles.MoveTo(in.X, in.Y)
+		handles.LineTo(out.X, out.Y)
+		dispatchPath(screen, &handles, 1)
+		drawCross(screen, out, diamondRadius-5)
+	}
+}
+
+// SplineFromStroke fits a spline through the corners SimplifyStroke finds in
+// a freehand stroke.
+func SplineFromStroke(points []SkatePoint) *SplinePath {
+	return NewSplineThrough(SimplifyStroke(points, SimplifyTolerance).Points)
+}
+
+// SplineFromRadiusPath returns a spline through the radius path's points.
+func SplineFromRadiusPath(rp *SkatePathWithRadius) *SplinePath {
+	s := NewSplineThrough(rp.Points)
+	s.Backwards = slices.Clone(rp.Backwards)
+	return s
+}
@@ -0,0 +1,51 @@
+package hg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplinePath(t *testing.T) {
+	points := []SkatePoint{{X: 0, Y: 0}, {X: 100, Y: 50}, {X: 200, Y: 0}, {X: 300, Y: 50}}
+	s := NewSplineThrough(points)
+	require.Len(t, s.Handles, 4)
+	assert.False(t, s.Editing())
+
+	// The curve passes through every point.
+	curve := s.pathPoints()
+	for i, p := range points {
+		assert.Equal(t, p, curve[i*splineSteps])
+	}
+	assert.Equal(t, points[0], s.Interpolate(0))
+	assert.InDelta(t, 300, s.Interpolate(1).X, 0.01)
+
+	// Equal fractions cover equal distances however the curve bends.
+	step := s.TotalLength() / 10
+	prev := s.Interpolate(0)
+	for i := 1; i <= 10; i++ {
+		p := s.Interpolate(float32(i) / 10)
+		assert.InDelta(t, step, p.Sub(prev).Length(), 1)
+		prev = p
+	}
+
+	rp := s.ToRadiusPath()
+	assert.Equal(t, points[0], rp.Points[0])
+	assert.Equal(t, len(curve), len(s.Polyline()))
+	assert.Equal(t, rp.Points, SplineFromRadiusPath(rp).Points)
+}
+
+func TestPathsLoadIdle(t *testing.T) {
+	for _, path := range []string{
+		`{"Type": "radius", "Points": [{"X": 0, "Y": 0}, {"X": 9, "Y": 9}], "PointRadiuses": [0, 0]}`,
+		`{"Type": "spline", "Points": [{"X": 0, "Y": 0}], "Handles": [{"X": 1, "Y": 0}]}`,
+	} {
+		p := &Player{}
+		require.NoError(t, decodeStrict([]byte(`{"X": 0, "Y": 0, "Id": 1, "Team": 0, "Symbol": "C", "Skating": null,
+		 "Timing": {"Delay": 0, "Finish": 0, "Ease": ""}, "Path": `+path+`}`), p))
+		require.Implements(t, (*EditablePath)(nil), p.Path)
+		assert.False(t, p.Path.(EditablePath).Editing())
+	}
+	assert.Error(t, decodeStrict([]byte(`{"Points": [], "Handles": [], "Tension": 1}`), &SplinePath{}))
+}
@@ -0,0 +1,142 @@
+package hg
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+	"regexp"
+	"strings"
+)
+
+var svgRootSize = regexp.MustCompile(`\s(width|height)="[^"]*"`)
+
+// rinkSVGElement returns the root element of rinkSVG sized to cover the
+// drill's pixel coordinate space, ready to nest inside another svg.
+func rinkSVGElement(rinkSVG []byte) (string, error) {
+	s := string(rinkSVG)
+	start := strings.Index(s, "<svg")
+	if start < 0 {
+		return "", errors.New("rink svg: no <svg> element")
+	}
+	s = s[start:]
+	tagEnd := strings.Index(s, ">")
+	if tagEnd < 0 {
+		return "", errors.New("rink svg: unterminated <svg> element")
+	}
+	root := svgRootSize.ReplaceAllString(s[:tagEnd], "")
+	root += fmt.Sprintf(` x="0" y="0" width="%d" height="%d" preserveAspectRatio="none"`, rinkPixelW, rinkPixelH)
+	return root + s[tagEnd:], nil
+}
+
+// SVGOptions control WriteDrillSVG.
+type SVGOptions struct {
+	// RinkSVG is drawn underneath everything in place of the rink's own
+	// markings, nil to draw the markings.
+	RinkSVG []byte
+	// VisibleFrame is the index of the only frame layer shown, or -1 to show them all.
+	VisibleFrame int
+}
+
+// WriteDrillSVG draws d as a vector diagram: the part of the rink its layout
+// shows with one layer per frame holding that frame's players at their
+// starting positions and their skate paths.  d's rink is the active one
+// while it writes.
+func WriteDrillSVG(w io.Writer, d *DrillFile, opts SVGOptions) error {
+	old := activeRink
+	useRink(d.Rink.View())
+	defer useRink(old)
+	b := activeRink.Bounds()
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<svg xmlns="http://www.w3.org/2000/svg" xmlns:inkscape="http://www.inkscape.org/namespaces/inkscape" width="%d" height="%d" viewBox="%d %d %d %d">
+`, b.Dx(), b.Dy(), b.Min.X, b.Min.Y, b.Dx(), b.Dy())
+	fmt.Fprintln(out, `<g id="rink" inkscape:groupmode="layer" inkscape:label="Rink">`)
+	if opts.RinkSVG != nil {
+		rinkElement, err := rinkSVGElement(opts.RinkSVG)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, rinkElement)
+	} else {
+		activeRink.writeSVG(out)
+	}
+	fmt.Fprintln(out, `</g>`)
+	for i, frame := range d.Frames {
+		display := ""
+		if opts.VisibleFrame >= 0 && opts.VisibleFrame < len(d.Frames) && i != opts.VisibleFrame {
+			display = ` style="display:none"`
+		}
+		fmt.Fprintf(out, `<g id="frame-%d" inkscape:groupmode="layer" inkscape:label="Frame %d"%s>
+`, i+1, i+1, display)
+		for _, prop := range frame.Props {
+			writeSVGProp(out, prop)
+		}
+		for _, player := range frame.Players.Players {
+			if player.Path != nil {
+				writeSVGStrokes(out, player.pathStrokes(), pathColor)
+			}
+		}
+		for _, player := range frame.Players.Players {
+			centre, heading := frame.poseAt(player, 0)
+			writeSVGPlayer(out, player, centre, heading, d.roster())
+		}
+		if frame.Puck != nil {
+			writeSVGPuck(out, frame.Puck, frame.Players)
+		}
+		fmt.Fprintln(out, `</g>`)
+	}
+	fmt.Fprintln(out, `</svg>`)
+	return out.Flush()
+}
+
+func svgPoints(points []SkatePoint) string {
+	var sb strings.Builder
+	for i, p := range points {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		fmt.Fprintf(&sb, "%.1f,%.1f", p.X, p.Y)
+	}
+	return sb.String()
+}
+
+// writeSVGPlayer draws the same shape and symbol as MakeSprite, centred on
+// centre and facing heading.
+func writeSVGPlayer(w io.Writer, p *Player, centre SkatePoint, heading float32, roster *Roster) {
+	col := roster.teamColor(p.Team)
+	var symbol bytes.Buffer
+	xml.EscapeText(&symbol, []byte(p.label()))
+	fmt.Fprint(w, `<g>`)
+	if outline := roleOutline(p.Role, centre, playerRadius); outline != nil {
+		fmt.Fprintf(w, `<polygon points="%s" fill="%s"/>`, svgPoints(outline), svgColor(col))
+	} else {
+		fmt.Fprintf(w, `<circle cx="%.1f" cy="%.1f" r="%d" fill="%s"/>`, centre.X, centre.Y, playerRadius, svgColor(col))
+	}
+	fmt.Fprintf(w, `<text x="%.1f" y="%.1f" font-family="sans-serif" font-size="%d" fill="white" text-anchor="middle" dominant-baseline="central">%s</text>`,
+		centre.X, centre.Y, playerRadius, symbol.String())
+	if p.Role != RoleMarker {
+		chevron, stick := facingMarks(centre, heading, p.Shoots)
+		fmt.Fprintf(w, `<polyline fill="none" stroke="%s" stroke-width="2" points="%s"/><polyline fill="none" stroke="%s" stroke-width="2" points="%s"/>`,
+			svgColor(facingColor), svgPoints(chevron[:]), svgColor(facingColor), svgPoints(stick[:]))
+	}
+	fmt.Fprintln(w, `</g>`)
+}
+
+// writeSVGPuck draws passes dashed and shots as a double line like
+// Puck.Draw, and the puck where it starts the frame.
+func writeSVGPuck(w io.Writer, pk *Puck, players *PlayerGroup) {
+	pk.eachMove(players, func(m *PuckMove, from, to SkatePoint) {
+		writeSVGStrokes(w, m.strokes(from, to), puckColor)
+	})
+	start, _ := pk.Position(players, 0)
+	fmt.Fprintf(w, `<circle cx="%.1f" cy="%.1f" r="%d" fill="%s"/>
+`, start.X, start.Y, puckRadius, svgColor(puckColor))
+}
+
+func svgColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
@@ -0,0 +1,44 @@
+package hg
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteDrillSVG(t *testing.T) {
+	frames := make([]frame, 3)
+	for i := range frames {
+		frames[i] = frame{Players: &PlayerGroup{}, DurationSeconds: 1}
+	}
+	d := &DrillFile{Rink: RinkLayout{Size: RinkIIHF, Area: RinkHalf}, Frames: frames}
+	editor := activeRink
+
+	tests := []struct {
+		visible int
+		hidden  []int
+	}{
+		{-1, nil},
+		{1, []int{1, 3}},
+		// Out of range shows everything rather than nothing.
+		{5, nil},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		require.NoError(t, WriteDrillSVG(&buf, d, SVGOptions{VisibleFrame: tt.visible}))
+		svg := buf.String()
+		assert.Equal(t, 3, strings.Count(svg, `inkscape:label="Frame `), "visible %d", tt.visible)
+		for i := 1; i <= 3; i++ {
+			layer := svg[strings.Index(svg, fmt.Sprintf(`id="frame-%d"`, i)):]
+			layer = layer[:strings.Index(layer, ">")]
+			assert.Equal(t, slices.Contains(tt.hidden, i), strings.Contains(layer, "display:none"), "visible %d frame %d", tt.visible, i)
+		}
+		// The editor's rink is left as it was.
+		assert.Same(t, editor, activeRink)
+	}
+}
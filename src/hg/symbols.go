@@ -44,11 +44,14 @@ func MakeCircle(letters string, r float32, clr color.Color) (*ebiten.Image, erro
 		FillRule:  ebiten.FillRuleNonZero,
 	}
 	img.DrawTriangles(vertices, indices, whiteSubImage, op)
+	return img, drawSpriteText(img, letters, r)
+}
 
-	// Draw the text
+// drawSpriteText writes letters in white in the middle of a sprite of radius r.
+func drawSpriteText(img *ebiten.Image, letters string, r float32) error {
 	s, err := text.NewGoTextFaceSource(bytes.NewReader(fonts.MPlus1pRegular_ttf))
 	if err != nil {
-		return img, err
+		return err
 	}
 	face := &text.GoTextFace{
 		Source: s,
@@ -59,8 +62,7 @@ func MakeCircle(letters string, r float32, clr color.Color) (*ebiten.Image, erro
 	textOp.GeoM.Translate(float64(r)-w/2, float64(r)-h/2)
 	textOp.ColorScale.ScaleWithColor(color.White)
 	text.Draw(img, letters, face, textOp)
-
-	return img, nil
+	return nil
 }
 
 type ButtonGroup struct {
@@ -143,6 +145,12 @@ func MakeButton(str string, w float32, h float32, x, y int, clr color.Color, cal
 	textOp.ColorScale.ScaleWithColor(color.White)
 	text.Draw(img, str, face, textOp)
 
+	return &Button{image: img, alphaImage: alphaImageOf(img), X: x, Y: y, callback: callback}, nil
+}
+
+// alphaImageOf copies just the alpha of img, for hit tests that would be
+// slow reading img back from the GPU.
+func alphaImageOf(img *ebiten.Image) *image.Alpha {
 	b := img.Bounds()
 	alphaImg := image.NewAlpha(b)
 	for j := b.Min.Y; j < b.Max.Y; j++ {
@@ -150,8 +158,7 @@ func MakeButton(str string, w float32, h float32, x, y int, clr color.Color, cal
 			alphaImg.Set(i, j, img.At(i, j))
 		}
 	}
-
-	return &Button{image: img, alphaImage: alphaImg, X: x, Y: y, callback: callback}, nil
+	return alphaImg
 }
 
 func (b *Button) Draw(screen *ebiten.Image) {
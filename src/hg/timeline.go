@@ -0,0 +1,153 @@
+package hg
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+)
+
+type timelineDrag int
+
+const (
+	timelineIdle timelineDrag = iota
+	timelinePlayhead
+	timelineMarker
+)
+
+// Timeline is the |---*----*--*---| scrubber under the rink.  It shows the
+// whole drill on one time axis with a marker at the end of each frame.
+// Dragging the playhead seeks, dragging a marker retimes the frame it ends.
+type Timeline struct {
+	Rect image.Rectangle
+
+	drag   timelineDrag
+	marker int
+	// viewSeconds is the time at the right hand end.  It is frozen while
+	// dragging so that retiming a frame doesn't rescale the axis under the mouse.
+	viewSeconds float64
+}
+
+const timelineMarkerGrab = 6
+
+func (t *Timeline) view(frames []frame) float64 {
+	if t.drag == timelineIdle {
+		t.viewSeconds = math.Ceil(drillDuration(frames)) + 1
+	}
+	return t.viewSeconds
+}
+
+func (t *Timeline) timeToX(frames []frame, seconds float64) float32 {
+	return float32(t.Rect.Min.X) + float32(seconds/t.view(frames))*float32(t.Rect.Dx())
+}
+
+func (t *Timeline) xToTime(frames []frame, x int) float64 {
+	s := float64(x-t.Rect.Min.X) / float64(t.Rect.Dx()) * t.view(frames)
+	return math.Min(t.view(frames), math.Max(0, s))
+}
+
+// In reports whether (x, y) is on the timeline.
+func (t *Timeline) In(x, y int) bool {
+	return image.Pt(x, y).In(t.Rect)
+}
+
+// Dragging reports whether a timeline drag is in progress.
+func (t *Timeline) Dragging() bool {
+	return t.drag != timelineIdle
+}
+
+// markerUnder returns the frame whose end marker is at x, or -1.
+func (t *Timeline) markerUnder(frames []frame, x int) int {
+	end := 0.0
+	found, best := -1, float32(timelineMarkerGrab)
+	for i, f := range frames {
+		end += f.DurationSeconds
+		if d := float32(math.Abs(float64(t.timeToX(frames, end) - float32(x)))); d <= best {
+			found, best = i, d
+		}
+	}
+	return found
+}
+
+// StartDrag begins a drag at (x, y) and reports whether the timeline took it.
+func (t *Timeline) StartDrag(frames []frame, x, y int) bool {
+	if !t.In(x, y) {
+		return false
+	}
+	t.view(frames)
+	if t.marker = t.markerUnder(frames, x); t.marker >= 0 {
+		t.drag = timelineMarker
+	} else {
+		t.drag = timelinePlayhead
+	}
+	return true
+}
+
+// Drag continues a drag to x.  Marker drags retime frames in place; playhead
+// drags return the drill time to seek to.
+func (t *Timeline) Drag(frames []frame, x int) (seek float64, seeking bool) {
+	switch t.drag {
+	case timelineMarker:
+		start := frameStartTime(frames, t.marker)
+		frames[t.marker].DurationSeconds = math.Max(0, t.xToTime(frames, x)-start)
+		return 0, false
+	case timelinePlayhead:
+		return t.xToTime(frames, x), true
+	}
+	return 0, false
+}
+
+// Retiming reports whether a frame marker is being dragged.
+func (t *Timeline) Retiming() bool {
+	return t.drag == timelineMarker
+}
+
+// EndDrag finishes any drag in progress.
+func (t *Timeline) EndDrag() {
+	t.drag = timelineIdle
+}
+
+// Draw draws the axis with one second ticks, the active frame highlighted,
+// the frame markers and the playhead at drill time now.
+func (t *Timeline) Draw(screen *ebiten.Image, frames []frame, activeFrame int, now float64) {
+	view := t.view(frames)
+	midY := float32(t.Rect.Min.Y+t.Rect.Max.Y) / 2
+	left, right := float32(t.Rect.Min.X), float32(t.Rect.Max.X)
+	grey := color.RGBA{0x80, 0x80, 0x80, 0xff}
+	black := color.Black
+
+	vector.StrokeLine(screen, left, midY, right, midY, 1, grey, true)
+	for s := 0.0; s <= view; s++ {
+		x := t.timeToX(frames, s)
+		vector.StrokeLine(screen, x, midY-3, x, midY+3, 1, grey, true)
+	}
+
+	start := frameStartTime(frames, activeFrame)
+	vector.StrokeLine(screen, t.timeToX(frames, start), midY,
+		t.timeToX(frames, start+frames[activeFrame].DurationSeconds), midY, 3, black, true)
+
+	vector.StrokeLine(screen, left, float32(t.Rect.Min.Y), left, float32(t.Rect.Max.Y), 2, black, true)
+	end := 0.0
+	for i, f := range frames {
+		end += f.DurationSeconds
+		x := t.timeToX(frames, end)
+		r := float32(4)
+		if t.drag == timelineMarker && t.marker == i {
+			r = 6
+		}
+		vector.DrawFilledCircle(screen, x, midY, r, black, true)
+	}
+
+	x := t.timeToX(frames, now)
+	red := color.RGBA{0xd0, 0, 0, 0xff}
+	vector.StrokeLine(screen, x, float32(t.Rect.Min.Y), x, float32(t.Rect.Max.Y), 2, red, true)
+}
+
+// DrawSpan highlights drill time from to to along the axis.
+func (t *Timeline) DrawSpan(screen *ebiten.Image, frames []frame, from, to float64, col color.Color) {
+	midY := float32(t.Rect.Min.Y+t.Rect.Max.Y) / 2
+	x0, x1 := t.timeToX(frames, from), t.timeToX(frames, to)
+	vector.StrokeLine(screen, x0, midY, max(x1, x0+2), midY, 5, col, true)
+}
@@ -0,0 +1,38 @@
+package hg
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimelineAxis(t *testing.T) {
+	tl := &Timeline{Rect: image.Rect(100, 0, 1100, 20)}
+	frames := []frame{{DurationSeconds: 1}, {DurationSeconds: 2}}
+
+	// Three seconds of drill and one spare across 1000 pixels.
+	for _, s := range []float64{0, 0.5, 1, 2.2, 3, 4} {
+		x := tl.timeToX(frames, s)
+		assert.InDelta(t, 100+s*250, x, 0.001, "seconds %v", s)
+		assert.InDelta(t, s, tl.xToTime(frames, int(x)), 0.001, "seconds %v", s)
+	}
+	assert.Equal(t, 0.0, tl.xToTime(frames, 50))
+	assert.Equal(t, 4.0, tl.xToTime(frames, 1200))
+
+	tests := []struct {
+		x      int
+		marker int
+	}{
+		{350, 0},
+		{350 - timelineMarkerGrab, 0},
+		{350 + timelineMarkerGrab + 1, -1},
+		{850, 1},
+		{848, 1},
+		{600, -1},
+		{100, -1},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.marker, tl.markerUnder(frames, tt.x), "x %d", tt.x)
+	}
+}